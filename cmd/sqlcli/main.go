@@ -2,10 +2,19 @@
 package main
 
 import (
+	"flag"
+	"os"
+
 	"github.com/phpCoder88/csv-searcher/internal/app"
 )
 
 func main() {
+	var opts app.Options
+	flag.StringVar(&opts.Query, "e", "", "execute the statements and quit")
+	flag.StringVar(&opts.ScriptFile, "f", "", "execute the statements from the file and quit")
+	flag.StringVar(&opts.Format, "format", "", "result output format: table, csv, tsv, json, ndjson or markdown")
+	flag.Parse()
+
 	appInst := app.NewApp()
-	appInst.Run()
+	os.Exit(appInst.Run(opts))
 }
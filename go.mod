@@ -1,11 +1,32 @@
 module github.com/phpCoder88/csv-searcher
 
-go 1.16
+go 1.22
 
 require (
 	github.com/joho/godotenv v1.3.0
 	github.com/kelseyhightower/envconfig v1.4.0
-	github.com/natefinch/lumberjack v2.0.0+incompatible // indirect
-	github.com/stretchr/testify v1.7.0
+	github.com/klauspost/compress v1.18.0
+	github.com/natefinch/lumberjack v2.0.0+incompatible
+	github.com/peterh/liner v1.2.1
+	github.com/stretchr/testify v1.8.4
+	github.com/ulikunitz/xz v0.5.15
+	github.com/xuri/excelize/v2 v2.9.0
 	go.uber.org/zap v1.16.0
+	golang.org/x/text v0.19.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/mattn/go-runewidth v0.0.3 // indirect
+	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/richardlehane/mscfb v1.0.4 // indirect
+	github.com/richardlehane/msoleps v1.0.4 // indirect
+	github.com/xuri/efp v0.0.0-20240408161823-9ad904a10d6d // indirect
+	github.com/xuri/nfp v0.0.0-20240318013403-ab9948c2c4a7 // indirect
+	go.uber.org/atomic v1.6.0 // indirect
+	go.uber.org/multierr v1.5.0 // indirect
+	golang.org/x/crypto v0.28.0 // indirect
+	golang.org/x/net v0.30.0 // indirect
 )
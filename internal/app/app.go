@@ -3,11 +3,14 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/phpCoder88/csv-searcher/internal/version"
@@ -21,10 +24,33 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+const (
+	exitOK    = 0
+	exitError = 1
+)
+
+// ErrScriptFile indicates that the script file can't be opened.
+var ErrScriptFile = errors.New("can't open script file")
+
+// Options describes command line options of the app.
+// The interactive monitor is started if Query and ScriptFile are empty and stdin is a terminal.
+type Options struct {
+	// Query contains statements given on the command line.
+	Query string
+	// ScriptFile is a path to a file with statements.
+	ScriptFile string
+	// Format overrides the configured result output format.
+	Format string
+}
+
 // A App describes app.
 type App struct {
 	conf   *config.Config
 	logger *zap.Logger
+
+	mu sync.Mutex
+	// cancelCurrent cancels the running query or reading of the statement.
+	cancelCurrent context.CancelFunc
 }
 
 // NewApp creates new app instance.
@@ -43,8 +69,8 @@ func NewApp() *App {
 	}
 }
 
-// Run runs the app.
-func (app *App) Run() {
+// Run runs the app and returns the process exit code.
+func (app *App) Run(opts Options) int {
 	defer func(logger *zap.Logger) {
 		err := logger.Sync()
 		if err != nil {
@@ -55,63 +81,228 @@ func (app *App) Run() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go app.waitSignal(cancel)
+	if opts.Format != "" {
+		app.conf.Format = opts.Format
+	}
 
-	appDir, err := os.Getwd()
+	err := app.setFormat(app.conf.Format)
 	if err != nil {
-		app.logger.Fatal(err.Error())
-		return
+		app.logger.Error(err.Error())
+		_, _ = fmt.Fprintln(os.Stderr, "ERROR:", err)
+		return exitError
 	}
 
-	fmt.Println("Welcome to the CsvDB monitor.")
-	fmt.Println("App Version is", version.Version)
-	fmt.Println("Build Date is", version.BuildDate)
-	fmt.Println("Build Commit is", version.BuildCommit)
-	fmt.Printf("Your location is %s\n\n", appDir)
-	fmt.Printf("Copyright (c) 2021 Bobylev Pavel.\n\n")
-	fmt.Printf("Type 'exit' to quit.\n\n")
+	input, interactive, err := app.openInput(opts)
+	if err != nil {
+		app.logger.Error(err.Error())
+		_, _ = fmt.Fprintln(os.Stderr, "ERROR:", err)
+		return exitError
+	}
+
+	defer func() {
+		err = input.Close()
+		if err != nil {
+			app.logger.Error(err.Error())
+		}
+	}()
+
+	connector := app.tableConnector(opts)
+
+	go app.waitSignal(cancel, interactive)
+
+	reader, err := app.newReader(input, interactive)
+	if err != nil {
+		app.logger.Error(err.Error())
+		_, _ = fmt.Fprintln(os.Stderr, "ERROR:", err)
+		return exitError
+	}
 
-	reader := sqlreader.NewSQLReader(os.Stdin)
+	defer func() {
+		err = reader.Close()
+		if err != nil {
+			app.logger.Error(err.Error())
+		}
+	}()
+
+	var interrupted bool
 	for {
-		fmt.Printf("CsvDB > ")
+		readCtx, readCancel := app.interruptible(ctx)
+		statement, err := reader.ReadStatement(readCtx)
+		readCancel()
+
+		if err == sqlreader.ErrInterrupted && ctx.Err() == nil {
+			if interrupted && reader.IsEmpty() {
+				fmt.Println("Bye")
+				app.logger.Info("Exiting...")
+				return exitOK
+			}
+
+			interrupted = true
+			reader.Reset()
+			fmt.Printf("(To exit, press Ctrl-C again or Ctrl-D)\n")
+			continue
+		}
 
-		input, err := reader.ReadLine(ctx)
 		if err != nil {
 			if err == io.EOF {
-				app.logger.Error("End of line")
-				fmt.Printf("\nBye\n")
+				app.logger.Info("End of input")
+				if interactive {
+					fmt.Printf("\nBye\n")
+				}
+				return exitOK
 			}
-			break
+			app.logger.Error(err.Error())
+			_, _ = fmt.Fprintln(os.Stderr, "ERROR:", err)
+			return exitError
 		}
 
-		if strings.EqualFold("exit", input) {
-			fmt.Println("Bye")
+		if strings.EqualFold(exitCommand, statement) {
+			if interactive {
+				fmt.Println("Bye")
+			}
 			app.logger.Info("Exiting...")
-			break
+			return exitOK
 		}
 
-		if input == "" {
-			continue
+		interrupted = false
+		if strings.HasPrefix(statement, string(sqlreader.CommandPrefix)) {
+			err = app.runCommand(statement)
+		} else {
+			queryCtx, queryCancel := app.interruptible(ctx)
+			err = db.Execute(queryCtx, connector, statement, app.conf, app.logger)
+			queryCancel()
 		}
-
-		err = db.Execute(ctx, db.FileTableConnector{}, input, app.conf, app.logger)
 		if err != nil {
 			app.logger.Error(err.Error())
 			_, _ = fmt.Fprintln(os.Stderr, "ERROR:", err)
+			if !interactive {
+				return exitError
+			}
 		}
 	}
 }
 
+// openInput returns the statements source and whether the monitor is interactive.
+func (app *App) openInput(opts Options) (io.ReadCloser, bool, error) {
+	if opts.Query != "" {
+		return io.NopCloser(strings.NewReader(opts.Query)), false, nil
+	}
+
+	if opts.ScriptFile != "" {
+		file, err := os.Open(opts.ScriptFile)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %v", ErrScriptFile, err)
+		}
+		return file, false, nil
+	}
+
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+
+	interactive := stat.Mode()&os.ModeCharDevice != 0
+	return io.NopCloser(os.Stdin), interactive, nil
+}
+
+// tableConnector returns the connector of tables, stdin is read as a table only if statements aren't read from it.
+func (app *App) tableConnector(opts Options) db.TableConnector {
+	var stdin io.Reader
+	if opts.Query != "" || opts.ScriptFile != "" {
+		stdin = os.Stdin
+	}
+
+	return db.NewStdinTableConnector(db.FileTableConnector{}, app.conf.TableLocation, stdin)
+}
+
+// newReader returns the statements reader, the interactive monitor gets line editing and history.
+func (app *App) newReader(input io.Reader, interactive bool) (*sqlreader.Reader, error) {
+	if !interactive {
+		return sqlreader.NewSQLReader(input), nil
+	}
+
+	app.printWelcome()
+
+	historyFile, err := expandHome(app.conf.HistoryFile)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := sqlreader.NewTerminalReader(historyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	reader.SetPrompt(os.Stdout, "CsvDB > ", "     -> ")
+	reader.SetCompleter(app.complete(db.NewCompleter(db.FileTableConnector{}, app.conf, app.logger)))
+	return reader, nil
+}
+
+// expandHome replaces the leading ~ of the path with the user home directory.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+// printWelcome prints the interactive monitor banner.
+func (app *App) printWelcome() {
+	appDir, err := os.Getwd()
+	if err != nil {
+		app.logger.Fatal(err.Error())
+		return
+	}
+
+	fmt.Println("Welcome to the CsvDB monitor.")
+	fmt.Println("App Version is", version.Version)
+	fmt.Println("Build Date is", version.BuildDate)
+	fmt.Println("Build Commit is", version.BuildCommit)
+	fmt.Printf("Your location is %s\n\n", appDir)
+	fmt.Printf("Copyright (c) 2021 Bobylev Pavel.\n\n")
+	fmt.Printf("Statements end with ';'. Type 'exit' to quit.\n\n")
+}
+
 // waitSignal handles SIGINT and SIGTERM signals.
-func (app *App) waitSignal(cancel context.CancelFunc) {
+// In the interactive mode SIGINT cancels only the running query or the statement being typed.
+func (app *App) waitSignal(cancel context.CancelFunc, interactive bool) {
 	osSignalChan := make(chan os.Signal, 1)
 	defer signal.Stop(osSignalChan)
 	signal.Notify(osSignalChan, syscall.SIGINT, syscall.SIGTERM)
 
-	sig := <-osSignalChan
-	fmt.Printf("\nAborted\n")
-	app.logger.Error(fmt.Sprintf("got signal %q", sig.String()))
-	cancel()
+	for sig := range osSignalChan {
+		if interactive && sig == syscall.SIGINT {
+			app.logger.Info("got SIGINT, canceling the current statement")
+			app.mu.Lock()
+			if app.cancelCurrent != nil {
+				app.cancelCurrent()
+			}
+			app.mu.Unlock()
+			continue
+		}
+
+		fmt.Printf("\nAborted\n")
+		app.logger.Error(fmt.Sprintf("got signal %q", sig.String()))
+		cancel()
+		return
+	}
+}
+
+// interruptible returns the context which is canceled by the next SIGINT in the interactive mode.
+func (app *App) interruptible(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	app.mu.Lock()
+	app.cancelCurrent = cancel
+	app.mu.Unlock()
+
+	return ctx, cancel
 }
 
 // createLogger configures zap logger.
@@ -1,13 +1,91 @@
 package app
 
 import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/db"
+	"github.com/phpCoder88/csv-searcher/internal/output"
 )
 
 func TestCreateLogger(t *testing.T) {
 	logger := createLogger()
 	assert.IsType(t, zap.Logger{}, *logger)
 }
+
+func TestApp_openInput(t *testing.T) {
+	app := &App{logger: zaptest.NewLogger(t)}
+
+	input, interactive, err := app.openInput(Options{Query: "select * from users;"})
+	assert.NoError(t, err)
+	assert.False(t, interactive)
+	content, _ := io.ReadAll(input)
+	assert.Equal(t, "select * from users;", string(content))
+
+	_, _, err = app.openInput(Options{ScriptFile: "not_exists.sql"})
+	assert.ErrorIs(t, err, ErrScriptFile)
+}
+
+func TestApp_runCommand(t *testing.T) {
+	app := &App{logger: zaptest.NewLogger(t), conf: &config.Config{Format: "table"}}
+
+	assert.NoError(t, app.runCommand(`\format JSON`))
+	assert.Equal(t, "json", app.conf.Format)
+
+	assert.ErrorIs(t, app.runCommand(`\format xml`), output.ErrUnknownFormat)
+	assert.Equal(t, "json", app.conf.Format)
+
+	assert.NoError(t, app.runCommand(`\header OFF`))
+	assert.False(t, app.conf.Header)
+	assert.NoError(t, app.runCommand(`\header on`))
+	assert.True(t, app.conf.Header)
+	assert.ErrorIs(t, app.runCommand(`\header yes`), ErrIncorrectArgument)
+
+	assert.ErrorIs(t, app.runCommand(`\unknown`), ErrUnknownCommand)
+}
+
+func TestApp_complete(t *testing.T) {
+	conf := &config.Config{Format: "table", TableLocation: t.TempDir()}
+	app := &App{logger: zaptest.NewLogger(t), conf: conf}
+	complete := app.complete(db.NewCompleter(db.FileTableConnector{}, conf, app.logger))
+
+	assert.Equal(t, []string{`\format`}, complete("", `\f`, ""))
+	assert.Len(t, complete(`\format `, "", ""), len(output.Formats))
+	assert.Equal(t, []string{"ndjson"}, complete(`\format `, "n", ""))
+	assert.Equal(t, []string{"off"}, complete(`\header `, "of", ""))
+	assert.Equal(t, []string{"exit"}, complete("", "exi", ""))
+	assert.Equal(t, []string{"explain", "exit"}, complete("", "ex", ""))
+	assert.Equal(t, []string{"select"}, complete("", "sel", ""))
+}
+
+func TestApp_tableConnector(t *testing.T) {
+	app := &App{logger: zaptest.NewLogger(t), conf: &config.Config{TableLocation: t.TempDir()}}
+
+	connector := app.tableConnector(Options{})
+	_, err := connector.GetReader(path.Join(app.conf.TableLocation, db.StdinTable))
+	assert.ErrorIs(t, err, db.ErrStdinUnavailable)
+
+	connector = app.tableConnector(Options{Query: "select a from stdin"})
+	assert.True(t, connector.Exists(path.Join(app.conf.TableLocation, db.StdinTableAlias)))
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	assert.NoError(t, err)
+
+	path, err := expandHome("~/.csvdb_history")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".csvdb_history"), path)
+
+	path, err = expandHome("./history")
+	assert.NoError(t, err)
+	assert.Equal(t, "./history", path)
+}
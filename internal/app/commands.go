@@ -0,0 +1,124 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/phpCoder88/csv-searcher/internal/db"
+	"github.com/phpCoder88/csv-searcher/internal/output"
+	"github.com/phpCoder88/csv-searcher/internal/sqlreader"
+)
+
+const (
+	// formatCommand shows or changes the result output format.
+	formatCommand = `\format`
+	// headerCommand shows or changes whether the first record of tables is the header.
+	headerCommand = `\header`
+)
+
+// exitCommand quits the monitor.
+const exitCommand = "exit"
+
+// commands lists the monitor commands for completion.
+var commands = []string{formatCommand, headerCommand}
+
+// headerValues lists arguments of the header command.
+var headerValues = []string{"on", "off"}
+
+var (
+	// ErrUnknownCommand indicates that the monitor command isn't supported.
+	ErrUnknownCommand = errors.New("unknown command")
+	// ErrIncorrectArgument indicates that the monitor command argument isn't supported.
+	ErrIncorrectArgument = errors.New("incorrect command argument")
+)
+
+// runCommand runs the monitor command.
+func (app *App) runCommand(command string) error {
+	args := strings.Fields(command)
+	app.logger.Info(fmt.Sprintf("Running command: '%s'", command))
+
+	switch strings.ToLower(args[0]) {
+	case formatCommand:
+		if len(args) == 1 {
+			fmt.Printf("Output format is %s\n\n", app.conf.Format)
+			return nil
+		}
+
+		return app.setFormat(args[1])
+
+	case headerCommand:
+		if len(args) == 1 {
+			state := headerValues[1]
+			if app.conf.Header {
+				state = headerValues[0]
+			}
+			fmt.Printf("Header is %s\n\n", state)
+			return nil
+		}
+
+		switch strings.ToLower(args[1]) {
+		case headerValues[0]:
+			app.conf.Header = true
+		case headerValues[1]:
+			app.conf.Header = false
+		default:
+			return fmt.Errorf("%w: '%s', expected on or off", ErrIncorrectArgument, args[1])
+		}
+		app.conf.HeaderSet = true
+		return nil
+	}
+
+	return fmt.Errorf("%w: '%s'", ErrUnknownCommand, args[0])
+}
+
+// setFormat validates and sets the session result output format.
+func (app *App) setFormat(name string) error {
+	format, err := output.ParseFormat(name)
+	if err != nil {
+		return err
+	}
+
+	app.conf.Format = string(format)
+	return nil
+}
+
+// complete completes monitor commands, their arguments and statements.
+func (app *App) complete(completer *db.Completer) sqlreader.Completer {
+	return func(before, word, after string) []string {
+		fields := strings.Fields(before)
+		if len(fields) == 0 && strings.HasPrefix(word, string(sqlreader.CommandPrefix)) {
+			return matchPrefix(commands, word)
+		}
+
+		if len(fields) == 1 && strings.EqualFold(fields[0], formatCommand) {
+			formats := make([]string, 0, len(output.Formats))
+			for _, format := range output.Formats {
+				formats = append(formats, string(format))
+			}
+			return matchPrefix(formats, word)
+		}
+
+		if len(fields) == 1 && strings.EqualFold(fields[0], headerCommand) {
+			return matchPrefix(headerValues, word)
+		}
+
+		completions := completer.Complete(before, word, after)
+		if len(fields) == 0 && word != "" {
+			completions = append(completions, matchPrefix([]string{exitCommand}, strings.ToLower(word))...)
+		}
+
+		return completions
+	}
+}
+
+func matchPrefix(words []string, prefix string) []string {
+	var result []string
+	for _, word := range words {
+		if strings.HasPrefix(word, prefix) {
+			result = append(result, word)
+		}
+	}
+
+	return result
+}
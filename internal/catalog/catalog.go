@@ -0,0 +1,240 @@
+// Package catalog reads per-table format options from the catalog file of the table location.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+)
+
+// FileName is the name of the catalog file in the table location.
+const FileName = "csvdb.yaml"
+
+// Column types which can be declared in the catalog.
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeDate    = "date"
+)
+
+// Input formats of tables, the format of a table which isn't declared is chosen by the file extension.
+const (
+	FormatCSV        = "csv"
+	FormatTSV        = "tsv"
+	FormatFixedWidth = "fixed"
+	FormatJSONLines  = "jsonl"
+	FormatXLSX       = "xlsx"
+	FormatParquet    = "parquet"
+)
+
+// DefaultDateLayouts are used for date columns without declared layouts and for date values in queries.
+var DefaultDateLayouts = []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339}
+
+// ErrIncorrectCatalog indicates that the catalog file can't be parsed or has incorrect options.
+var ErrIncorrectCatalog = errors.New("incorrect catalog")
+
+// Catalog describes the catalog file.
+type Catalog struct {
+	Tables map[string]TableOptions `yaml:"tables"`
+}
+
+// TableOptions describes format options of a table, zero values mean the session defaults.
+type TableOptions struct {
+	Format    string `yaml:"format"`
+	Delimiter string `yaml:"delimiter"`
+	Quote     string `yaml:"quote"`
+	Comment   string `yaml:"comment"`
+	Header    *bool  `yaml:"header"`
+	// Columns maps column names to their types.
+	Columns map[string]string `yaml:"columns"`
+	// Null lists values which mean an empty value.
+	Null        []string `yaml:"null_values"`
+	DateLayouts []string `yaml:"date_layouts"`
+	// Encoding is auto or a label of the table encoding, e.g. windows-1251.
+	Encoding string `yaml:"encoding"`
+	// Layout describes columns of a fixed-width table.
+	Layout []FixedColumn `yaml:"layout"`
+	// Sheet is the worksheet of an Excel workbook, the first one by default.
+	Sheet string `yaml:"sheet"`
+}
+
+// FixedColumn describes a column of a fixed-width table, Start is the 1-based position of the first character.
+type FixedColumn struct {
+	Name   string `yaml:"name"`
+	Start  int    `yaml:"start"`
+	Length int    `yaml:"length"`
+}
+
+// ValidateLayout checks that columns have names and positive positions and lengths.
+func ValidateLayout(layout []FixedColumn) error {
+	for i, column := range layout {
+		switch {
+		case column.Name == "":
+			return fmt.Errorf("layout column %d has no name", i+1)
+		case column.Start < 1:
+			return fmt.Errorf("start of layout column '%s' must be positive", column.Name)
+		case column.Length < 1:
+			return fmt.Errorf("length of layout column '%s' must be positive", column.Name)
+		}
+	}
+
+	return nil
+}
+
+// Parse reads and validates the catalog.
+func Parse(r io.Reader) (*Catalog, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var catalog Catalog
+	err := decoder.Decode(&catalog)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%w: %v", ErrIncorrectCatalog, err)
+	}
+
+	for name, options := range catalog.Tables {
+		err = options.validate()
+		if err != nil {
+			return nil, fmt.Errorf("%w: table '%s': %v", ErrIncorrectCatalog, name, err)
+		}
+	}
+
+	return &catalog, nil
+}
+
+// Table returns options of the table, the catalog may be nil.
+func (c *Catalog) Table(name string) TableOptions {
+	if c == nil {
+		return TableOptions{}
+	}
+
+	return c.Tables[name]
+}
+
+func (o *TableOptions) validate() error {
+	options := []struct{ name, value string }{
+		{"delimiter", o.Delimiter},
+		{"quote", o.Quote},
+		{"comment", o.Comment},
+	}
+	for _, option := range options {
+		if option.value != "" && utf8.RuneCountInString(option.value) != 1 {
+			return fmt.Errorf("%s must be one character", option.name)
+		}
+	}
+
+	if o.Quote != "" && (o.Quote[0] >= utf8.RuneSelf || o.Quote == "\r" || o.Quote == "\n") {
+		return errors.New("quote must be an ASCII character")
+	}
+
+	if o.Quote != "" && o.Quote == o.Delimiter {
+		return errors.New("quote and delimiter must be different")
+	}
+
+	switch o.Format {
+	case "", FormatCSV, FormatTSV, FormatFixedWidth, FormatJSONLines, FormatXLSX, FormatParquet:
+	default:
+		return fmt.Errorf("unknown format '%s'", o.Format)
+	}
+
+	if len(o.Layout) > 0 && o.Format != "" && o.Format != FormatFixedWidth {
+		return fmt.Errorf("layout is given for %s format", o.Format)
+	}
+
+	if o.Sheet != "" && o.Format != "" && o.Format != FormatXLSX {
+		return fmt.Errorf("sheet is given for %s format", o.Format)
+	}
+
+	err := ValidateLayout(o.Layout)
+	if err != nil {
+		return err
+	}
+
+	for column, typ := range o.Columns {
+		switch typ {
+		case TypeString, TypeInteger, TypeNumber, TypeDate:
+		default:
+			return fmt.Errorf("unknown type '%s' of column '%s'", typ, column)
+		}
+	}
+
+	if o.Encoding != "" && !config.IsEncoding(o.Encoding) {
+		return fmt.Errorf("unsupported encoding '%s'", o.Encoding)
+	}
+
+	return nil
+}
+
+// DelimiterRune returns the field delimiter or def if it isn't set.
+func (o TableOptions) DelimiterRune(def rune) rune {
+	return optionRune(o.Delimiter, def)
+}
+
+// QuoteByte returns the quote character, double quote by default.
+func (o TableOptions) QuoteByte() byte {
+	if o.Quote == "" {
+		return '"'
+	}
+
+	return o.Quote[0]
+}
+
+// CommentRune returns the comment character or zero if comments aren't allowed.
+func (o TableOptions) CommentRune() rune {
+	return optionRune(o.Comment, 0)
+}
+
+// HasHeader returns whether the first record is the header or def if it isn't set.
+func (o TableOptions) HasHeader(def bool) bool {
+	if o.Header == nil {
+		return def
+	}
+
+	return *o.Header
+}
+
+// ColumnType returns the declared type of the column or empty string.
+func (o TableOptions) ColumnType(column string) string {
+	return o.Columns[column]
+}
+
+// IsNull returns true if the value is one of the NULL tokens.
+func (o TableOptions) IsNull(value string) bool {
+	for _, token := range o.Null {
+		if value == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseDate parses the value with the declared date layouts and the default ones.
+func (o TableOptions) ParseDate(value string) (time.Time, bool) {
+	for _, layouts := range [][]string{o.DateLayouts, DefaultDateLayouts} {
+		for _, layout := range layouts {
+			date, err := time.Parse(layout, value)
+			if err == nil {
+				return date, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func optionRune(value string, def rune) rune {
+	if value == "" {
+		return def
+	}
+
+	r, _ := utf8.DecodeRuneInString(value)
+	return r
+}
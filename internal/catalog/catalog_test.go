@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	content := `
+tables:
+  users.csv:
+    delimiter: ";"
+    quote: "'"
+    comment: "#"
+    header: false
+    columns:
+      created: date
+      age: integer
+    null_values: ["NULL", "\\N"]
+    date_layouts: ["02.01.2006"]
+    encoding: UTF-8
+  roles.csv:
+    delimiter: "|"
+  events.log:
+    format: jsonl
+  accounts.dat:
+    layout:
+      - {name: id, start: 1, length: 6}
+      - {name: name, start: 7, length: 20}
+  report.xlsx:
+    sheet: Totals
+  history.dat:
+    format: parquet
+`
+
+	catalog, err := Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	users := catalog.Table("users.csv")
+	assert.Equal(t, ';', users.DelimiterRune(','))
+	assert.Equal(t, byte('\''), users.QuoteByte())
+	assert.Equal(t, '#', users.CommentRune())
+	assert.False(t, users.HasHeader(true))
+	assert.Equal(t, TypeDate, users.ColumnType("created"))
+	assert.Equal(t, "", users.ColumnType("name"))
+	assert.True(t, users.IsNull(`\N`))
+	assert.False(t, users.IsNull(""))
+
+	date, ok := users.ParseDate("31.12.2020")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), date)
+	_, ok = users.ParseDate("2020-12-31")
+	assert.True(t, ok)
+	_, ok = users.ParseDate("yesterday")
+	assert.False(t, ok)
+
+	roles := catalog.Table("roles.csv")
+	assert.Equal(t, '|', roles.DelimiterRune(','))
+	assert.Equal(t, byte('"'), roles.QuoteByte())
+	assert.Equal(t, rune(0), roles.CommentRune())
+	assert.True(t, roles.HasHeader(true))
+	assert.Equal(t, FormatJSONLines, catalog.Table("events.log").Format)
+	assert.Equal(t, []FixedColumn{{Name: "id", Start: 1, Length: 6}, {Name: "name", Start: 7, Length: 20}},
+		catalog.Table("accounts.dat").Layout)
+	assert.Equal(t, "Totals", catalog.Table("report.xlsx").Sheet)
+	assert.Equal(t, FormatParquet, catalog.Table("history.dat").Format)
+
+	assert.Equal(t, TableOptions{}, catalog.Table("unknown.csv"))
+	assert.Equal(t, TableOptions{}, (*Catalog)(nil).Table("users.csv"))
+}
+
+func TestParse_empty(t *testing.T) {
+	catalog, err := Parse(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Equal(t, TableOptions{}, catalog.Table("users.csv"))
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []string{
+		"tables: [",
+		"tables:\n  users.csv:\n    unknown: 1\n",
+		"tables:\n  users.csv:\n    delimiter: ';;'\n",
+		"tables:\n  users.csv:\n    quote: 'ё'\n",
+		"tables:\n  users.csv:\n    quote: ';'\n    delimiter: ';'\n",
+		"tables:\n  users.csv:\n    columns:\n      id: bigint\n",
+		"tables:\n  users.csv:\n    encoding: ebcdic\n",
+		"tables:\n  users.csv:\n    format: xml\n",
+		"tables:\n  users.csv:\n    format: tsv\n    layout: [{name: id, start: 1, length: 6}]\n",
+		"tables:\n  users.dat:\n    layout: [{name: id, start: 0, length: 6}]\n",
+		"tables:\n  users.dat:\n    layout: [{name: id, start: 1}]\n",
+		"tables:\n  users.dat:\n    layout: [{start: 1, length: 6}]\n",
+		"tables:\n  users.csv:\n    format: csv\n    sheet: Sheet1\n",
+	}
+
+	for _, content := range tests {
+		_, err := Parse(strings.NewReader(content))
+		assert.ErrorIs(t, err, ErrIncorrectCatalog, content)
+	}
+}
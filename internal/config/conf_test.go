@@ -15,6 +15,14 @@ func TestGetConfig_default(t *testing.T) {
 		TableLocation:  "./",
 		Limit:          100,
 		Delimiter:      ",",
+		Header:         true,
+		Sniff:          true,
+		ParseMode:      "strict",
+		Encoding:       "auto",
+		Format:         "table",
+		PreviewRows:    1000,
+		Ordered:        true,
+		HistoryFile:    "~/.csvdb_history",
 		FieldDelimiter: ',',
 	}
 	assert.NoError(t, err)
@@ -29,6 +37,47 @@ func TestGetConfig_DelimiterError(t *testing.T) {
 	_ = os.Unsetenv("DELIMITER")
 }
 
+func TestGetConfig_explicitDialect(t *testing.T) {
+	_ = os.Setenv("HEADER", "true")
+	conf, err := GetConfig()
+	assert.NoError(t, err)
+	assert.True(t, conf.HeaderSet)
+	assert.False(t, conf.DelimiterSet)
+	_ = os.Unsetenv("HEADER")
+
+	_ = os.Setenv("DELIMITER", ",")
+	conf, err = GetConfig()
+	assert.NoError(t, err)
+	assert.False(t, conf.HeaderSet)
+	assert.True(t, conf.DelimiterSet)
+	_ = os.Unsetenv("DELIMITER")
+}
+
+func TestGetConfig_ParseModeError(t *testing.T) {
+	_ = os.Setenv("PARSEMODE", "careless")
+	conf, err := GetConfig()
+	assert.Equal(t, ErrIncorrectParseMode, err)
+	assert.Nil(t, conf)
+	_ = os.Unsetenv("PARSEMODE")
+}
+
+func TestGetConfig_EncodingError(t *testing.T) {
+	_ = os.Setenv("ENCODING", "ebcdic")
+	conf, err := GetConfig()
+	assert.Equal(t, ErrIncorrectEncoding, err)
+	assert.Nil(t, conf)
+	_ = os.Unsetenv("ENCODING")
+}
+
+func TestIsEncoding(t *testing.T) {
+	for _, name := range []string{"auto", "AUTO", "utf-8", "UTF8", "windows-1251", "cp1252", "utf-16le", "latin1"} {
+		assert.True(t, IsEncoding(name), name)
+	}
+	for _, name := range []string{"", "ebcdic", "utf-32"} {
+		assert.False(t, IsEncoding(name), name)
+	}
+}
+
 func TestGetConfig_EnvFile(t *testing.T) {
 	file, err := os.Create(".env")
 	if err != nil {
@@ -51,7 +100,16 @@ DELIMITER=";"`
 		TableLocation:  "./testdata",
 		Limit:          1000,
 		Delimiter:      ";",
+		Header:         true,
+		Sniff:          true,
+		ParseMode:      "strict",
+		Encoding:       "auto",
+		Format:         "table",
+		PreviewRows:    1000,
+		Ordered:        true,
+		HistoryFile:    "~/.csvdb_history",
 		FieldDelimiter: ';',
+		DelimiterSet:   true,
 	}
 	assert.NoError(t, err)
 	assert.Equal(t, expectedConf, *conf)
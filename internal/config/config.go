@@ -4,11 +4,13 @@ package config
 import (
 	"errors"
 	"os"
+	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
+	"golang.org/x/text/encoding/htmlindex"
 )
 
 // Config describes configuration data.
@@ -18,11 +20,40 @@ type Config struct {
 	TableLocation  string        `default:"./"`
 	Limit          int32         `default:"100"`
 	Delimiter      string        `default:","`
+	Header         bool          `default:"true"`
+	Sniff          bool          `default:"true"`
+	ParseMode      string        `default:"strict"`
+	Encoding       string        `default:"auto"`
+	Format         string        `default:"table"`
+	PreviewRows    int           `default:"1000"`
+	Ordered        bool          `default:"true"`
+	HistoryFile    string        `default:"~/.csvdb_history"`
 	FieldDelimiter rune
+	// HeaderSet and DelimiterSet are true if HEADER and DELIMITER are set explicitly,
+	// the detected dialect doesn't override them then.
+	HeaderSet    bool `ignored:"true"`
+	DelimiterSet bool `ignored:"true"`
 }
 
-// ErrIncorrectDelimiter is error for incorrect delimiter.
-var ErrIncorrectDelimiter = errors.New("incorrect delimiter. there must be only one rune in string")
+// Parse modes define what happens to table rows which can't be parsed.
+const (
+	// ParseModeStrict fails the query on the first malformed row.
+	ParseModeStrict = "strict"
+	// ParseModeLenient skips malformed rows and reports them after the result.
+	ParseModeLenient = "lenient"
+)
+
+// EncodingAuto means that the table encoding is detected by the table beginning.
+const EncodingAuto = "auto"
+
+var (
+	// ErrIncorrectDelimiter is error for incorrect delimiter.
+	ErrIncorrectDelimiter = errors.New("incorrect delimiter. there must be only one rune in string")
+	// ErrIncorrectParseMode is error for unknown parse mode.
+	ErrIncorrectParseMode = errors.New("incorrect parse mode. it must be strict or lenient")
+	// ErrIncorrectEncoding is error for unknown encoding.
+	ErrIncorrectEncoding = errors.New("incorrect encoding")
+)
 
 // GetConfig returns configuration data from .env file or env variables.
 func GetConfig() (*Config, error) {
@@ -41,10 +72,31 @@ func GetConfig() (*Config, error) {
 		return nil, err
 	}
 
+	_, conf.HeaderSet = os.LookupEnv("HEADER")
+	_, conf.DelimiterSet = os.LookupEnv("DELIMITER")
+
 	if utf8.RuneCountInString(conf.Delimiter) != 1 {
 		return nil, ErrIncorrectDelimiter
 	}
 
+	if conf.ParseMode != ParseModeStrict && conf.ParseMode != ParseModeLenient {
+		return nil, ErrIncorrectParseMode
+	}
+
+	if !IsEncoding(conf.Encoding) {
+		return nil, ErrIncorrectEncoding
+	}
+
 	conf.FieldDelimiter, _ = utf8.DecodeRuneInString(conf.Delimiter)
 	return &conf, nil
 }
+
+// IsEncoding returns true if the name is auto or a label of a known encoding, e.g. windows-1251 or utf-16le.
+func IsEncoding(name string) bool {
+	if strings.EqualFold(name, EncodingAuto) {
+		return true
+	}
+
+	_, err := htmlindex.Get(name)
+	return err == nil
+}
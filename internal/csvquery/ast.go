@@ -0,0 +1,202 @@
+package csvquery
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Node describes any node of the query syntax tree.
+type Node interface {
+	String() string
+}
+
+// Statement describes a query statement node.
+type Statement interface {
+	Node
+	statementNode()
+}
+
+// Expr describes an expression node of the where statement.
+type Expr interface {
+	Node
+	exprNode()
+}
+
+// Literal describes a constant value node.
+type Literal interface {
+	Node
+	literalNode()
+}
+
+// SelectStatement describes a parsed SELECT statement.
+type SelectStatement struct {
+	Select Columns
+	From   Tables
+	Where  Expr
+	Into   *OutFile
+}
+
+// BinaryExpr describes two expressions joined by a logical operator.
+type BinaryExpr struct {
+	Op    LogicalOperator
+	Left  Expr
+	Right Expr
+}
+
+// NumberLiteral describes a number constant.
+type NumberLiteral float64
+
+// StringLiteral describes a string constant.
+type StringLiteral string
+
+func (*SelectStatement) statementNode() {}
+
+func (*BinaryExpr) exprNode() {}
+func (*Condition) exprNode()  {}
+
+func (NumberLiteral) literalNode() {}
+func (StringLiteral) literalNode() {}
+
+// String returns the statement as a query string.
+func (s *SelectStatement) String() string {
+	var b strings.Builder
+
+	b.WriteString(string(SelectKeyword))
+	b.WriteString(" ")
+	for i, col := range s.Select {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(col.String())
+	}
+
+	b.WriteString(" ")
+	b.WriteString(string(FromKeyword))
+	b.WriteString(" ")
+	for i, table := range s.From {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(table.String())
+	}
+
+	if s.Where != nil {
+		b.WriteString(" ")
+		b.WriteString(string(WhereKeyword))
+		b.WriteString(" ")
+		b.WriteString(s.Where.String())
+	}
+
+	if s.Into != nil {
+		b.WriteString(" ")
+		b.WriteString(s.Into.String())
+	}
+
+	return b.String()
+}
+
+// String returns the expression as a where statement string.
+// Brackets are added only where the operator priority requires them.
+func (e *BinaryExpr) String() string {
+	left := e.Left.String()
+	if exprPriority(e.Left) < GetPriority(e.Op) {
+		left = "(" + left + ")"
+	}
+
+	right := e.Right.String()
+	if exprPriority(e.Right) <= GetPriority(e.Op) {
+		right = "(" + right + ")"
+	}
+
+	return left + " " + string(e.Op) + " " + right
+}
+
+// String returns the condition as a where statement string.
+func (c *Condition) String() string {
+	value := "NULL"
+	if c.Value != nil {
+		value = c.Value.String()
+	}
+
+	return c.Column.String() + " " + string(c.Op) + " " + value
+}
+
+// String returns the number as it is written in a query.
+func (l NumberLiteral) String() string {
+	return strconv.FormatFloat(float64(l), 'f', -1, 64)
+}
+
+// String returns the quoted string as it is written in a query.
+func (l StringLiteral) String() string {
+	if !strings.Contains(string(l), "'") {
+		return "'" + string(l) + "'"
+	}
+
+	return `"` + strings.ReplaceAll(string(l), `"`, `\"`) + `"`
+}
+
+// String returns the column name.
+func (c Column) String() string {
+	return string(c)
+}
+
+// String returns the table name.
+func (t Table) String() string {
+	return string(t)
+}
+
+// exprPriority returns priority of the expression operator.
+// Conditions bind tighter than any logical operator.
+func exprPriority(expr Expr) int {
+	if binExpr, ok := expr.(*BinaryExpr); ok {
+		return GetPriority(binExpr.Op)
+	}
+
+	return GetPriority(AndOperator) + 1
+}
+
+// Walk traverses the syntax tree in depth-first order calling fn for each node.
+// Children of a node are skipped if fn returns false.
+func Walk(node Node, fn func(Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *SelectStatement:
+		for _, col := range n.Select {
+			Walk(col, fn)
+		}
+		for _, table := range n.From {
+			Walk(table, fn)
+		}
+		Walk(n.Where, fn)
+		if n.Into != nil {
+			Walk(n.Into, fn)
+		}
+	case *BinaryExpr:
+		Walk(n.Left, fn)
+		Walk(n.Right, fn)
+	case *Condition:
+		Walk(n.Column, fn)
+		Walk(n.Value, fn)
+	}
+}
+
+// Rewrite returns the expression tree with every expression replaced by the result of fn.
+// Children are rewritten before their parents, the original tree isn't modified.
+func Rewrite(expr Expr, fn func(Expr) Expr) Expr {
+	if expr == nil {
+		return nil
+	}
+
+	if binExpr, ok := expr.(*BinaryExpr); ok {
+		expr = &BinaryExpr{
+			Op:    binExpr.Op,
+			Left:  Rewrite(binExpr.Left, fn),
+			Right: Rewrite(binExpr.Right, fn),
+		}
+	}
+
+	return fn(expr)
+}
@@ -0,0 +1,93 @@
+package csvquery
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectStatement_String(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantString string
+	}{
+		{
+			query:      "select * from users",
+			wantString: "SELECT * FROM users",
+		},
+		{
+			query:      "select name,age from users, roles where age = 33",
+			wantString: "SELECT name, age FROM users, roles WHERE age = 33",
+		},
+		{
+			query:      "select name from users where age <= 54.5 or country = 'Europe' and name != \"O'Neil\"",
+			wantString: `SELECT name FROM users WHERE age <= 54.5 OR country = 'Europe' AND name != "O'Neil"`,
+		},
+		{
+			query:      "select name from users where (age < 30 or age > 50) and (name = 'Bob')",
+			wantString: "SELECT name FROM users WHERE (age < 30 OR age > 50) AND name = 'Bob'",
+		},
+		{
+			query:      "select name from users where age < 30 and (age > 10 and name = 'Bob')",
+			wantString: "SELECT name FROM users WHERE age < 30 AND (age > 10 AND name = 'Bob')",
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query := NewQuery(tt.query, logger)
+			assert.NoError(t, query.Parse())
+			assert.Equal(t, tt.wantString, query.Statement().String())
+
+			reparsed := NewQuery(tt.wantString, logger)
+			assert.NoError(t, reparsed.Parse())
+			assert.Equal(t, query.Statement(), reparsed.Statement())
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	cond1 := &Condition{Column: "age", Op: "<", Value: NumberLiteral(30)}
+	cond2 := &Condition{Column: "name", Op: "=", Value: StringLiteral("Bob")}
+	stmt := &SelectStatement{
+		Select: Columns{"name"},
+		From:   Tables{"users"},
+		Where:  &BinaryExpr{Op: AndOperator, Left: cond1, Right: cond2},
+	}
+
+	var columns []Column
+	var conditions int
+	Walk(stmt, func(node Node) bool {
+		switch n := node.(type) {
+		case Column:
+			columns = append(columns, n)
+		case *Condition:
+			conditions++
+			return false
+		}
+		return true
+	})
+
+	assert.Equal(t, []Column{"name"}, columns)
+	assert.Equal(t, 2, conditions)
+}
+
+func TestRewrite(t *testing.T) {
+	cond1 := &Condition{Column: "age", Op: "<", Value: NumberLiteral(30)}
+	cond2 := &Condition{Column: "name", Op: "=", Value: StringLiteral("Bob")}
+	where := &BinaryExpr{Op: AndOperator, Left: cond1, Right: cond2}
+
+	rewritten := Rewrite(where, func(expr Expr) Expr {
+		if binExpr, ok := expr.(*BinaryExpr); ok {
+			binExpr.Op = OrOperator
+		}
+		return expr
+	})
+
+	assert.Equal(t, "age < 30 AND name = 'Bob'", where.String())
+	assert.Equal(t, "age < 30 OR name = 'Bob'", rewritten.String())
+	assert.Nil(t, Rewrite(nil, func(expr Expr) Expr { return expr }))
+}
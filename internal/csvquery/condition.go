@@ -6,16 +6,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
-)
-
-// ValueType describes value type of condition value.
-type ValueType uint
-
-const (
-	// TypeNumber return condition number value type.
-	TypeNumber ValueType = iota
-	// TypeString return condition string value type.
-	TypeString
+	"time"
 )
 
 var (
@@ -23,20 +14,15 @@ var (
 	ErrUnknownValueType = errors.New("unknown value type of condition value")
 	// ErrUnknownComparisonOperator describes unknown comparison operator error.
 	ErrUnknownComparisonOperator = errors.New("unknown comparison operator")
-	// ErrCastInterfaceToString error if script can't cast interface to string.
-	ErrCastInterfaceToString = errors.New("can't cast interface to string")
-	// ErrCastInterfaceToFloat64 error if script can't cast interface to float64.
-	ErrCastInterfaceToFloat64 = errors.New("can't cast interface to float64")
 	// ErrConvertToFloat64 error if script can't convert float64.
 	ErrConvertToFloat64 = errors.New("can't convert float64")
 )
 
 // Condition describes one condition in where statement.
 type Condition struct {
-	Column    Column
-	Op        ComparisonOperator
-	ValueType ValueType
-	Value     interface{}
+	Column Column
+	Op     ComparisonOperator
+	Value  Literal
 }
 
 // ConditionPrefix contains string condition prefix which is using in ConditionMap.
@@ -68,26 +54,22 @@ func (cm *ConditionMap) exists(cond *Condition) (string, bool) {
 
 // CheckCondition checks condition.
 func (c *Condition) CheckCondition(value string) (bool, error) {
-	if c.ValueType == TypeNumber {
-		return c.checkNumberCondition(value)
-	} else if c.ValueType == TypeString {
-		return c.checkStringCondition(value)
+	switch condValue := c.Value.(type) {
+	case NumberLiteral:
+		return c.checkNumberCondition(value, float64(condValue))
+	case StringLiteral:
+		return c.checkStringCondition(value, string(condValue))
 	}
 
-	return false, fmt.Errorf("%w: column: %s, condition value: %s, value Type: %d", ErrUnknownValueType, c.Column, value, c.ValueType)
+	return false, fmt.Errorf("%w: column: %s, condition value: %s, value: %v", ErrUnknownValueType, c.Column, value, c.Value)
 }
 
 // checkNumberCondition checks number condition.
-func (c *Condition) checkNumberCondition(value string) (bool, error) {
+func (c *Condition) checkNumberCondition(value string, condValue float64) (bool, error) {
 	if strings.TrimSpace(value) == "" {
 		return false, nil
 	}
 
-	condValue, ok := c.Value.(float64)
-	if !ok {
-		return false, fmt.Errorf("%w: column: %s, condition value: %v", ErrCastInterfaceToFloat64, c.Column, c.Value)
-	}
-
 	colNumberValue, err := strconv.ParseFloat(value, 64)
 	if err != nil {
 		return false, fmt.Errorf("%w: column: %s, row value: %v", ErrConvertToFloat64, c.Column, value)
@@ -119,13 +101,28 @@ func (c *Condition) checkNumberCondition(value string) (bool, error) {
 	return false, fmt.Errorf("%w: column: %s, operator: %s", ErrUnknownComparisonOperator, c.Column, c.Op)
 }
 
-// checkStringCondition checks string condition.
-func (c *Condition) checkStringCondition(value string) (bool, error) {
-	condValue, ok := c.Value.(string)
-	if !ok {
-		return false, fmt.Errorf("%w: column: %s, condition value: %v", ErrCastInterfaceToString, c.Column, c.Value)
+// CheckTimeCondition checks condition of a date column, the row value and the condition value are already parsed.
+func (c *Condition) CheckTimeCondition(value, condValue time.Time) (bool, error) {
+	switch c.Op {
+	case EqualOperator:
+		return value.Equal(condValue), nil
+	case NotEqualOperator:
+		return !value.Equal(condValue), nil
+	case LessOperator:
+		return value.Before(condValue), nil
+	case LessOrEqualOperator:
+		return !value.After(condValue), nil
+	case GreaterOperator:
+		return value.After(condValue), nil
+	case GreaterOrEqualOperator:
+		return !value.Before(condValue), nil
 	}
 
+	return false, fmt.Errorf("%w: column: %s, operator: %s", ErrUnknownComparisonOperator, c.Column, c.Op)
+}
+
+// checkStringCondition checks string condition.
+func (c *Condition) checkStringCondition(value, condValue string) (bool, error) {
 	switch c.Op {
 	case EqualOperator:
 		return value == condValue, nil
@@ -3,6 +3,7 @@ package csvquery
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -11,31 +12,27 @@ func TestConditionMap_Add(t *testing.T) {
 	condMap := make(ConditionMap)
 
 	cond0 := &Condition{
-		Column:    "age",
-		Op:        "<=",
-		ValueType: TypeNumber,
-		Value:     30,
+		Column: "age",
+		Op:     "<=",
+		Value:  NumberLiteral(30),
 	}
 
 	cond1 := &Condition{
-		Column:    "date",
-		Op:        ">",
-		ValueType: TypeString,
-		Value:     "2021-01-01",
+		Column: "date",
+		Op:     ">",
+		Value:  StringLiteral("2021-01-01"),
 	}
 
 	cond2 := &Condition{
-		Column:    "age",
-		Op:        "<=",
-		ValueType: TypeNumber,
-		Value:     30,
+		Column: "age",
+		Op:     "<=",
+		Value:  NumberLiteral(30),
 	}
 
 	cond3 := &Condition{
-		Column:    "date",
-		Op:        ">",
-		ValueType: TypeString,
-		Value:     "2021-01-01",
+		Column: "date",
+		Op:     ">",
+		Value:  StringLiteral("2021-01-01"),
 	}
 
 	condKey0 := condMap.Add(cond0)
@@ -60,75 +57,75 @@ func TestNumberCondition_CheckCondition(t *testing.T) {
 	}{
 		{
 			name:     "'' == 30",
-			cond:     &Condition{Column: "age", Op: "=", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: "=", Value: NumberLiteral(30)},
 			colValue: "",
 		},
 		{
 			name:     "25 == 30",
-			cond:     &Condition{Column: "age", Op: "=", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: "=", Value: NumberLiteral(30)},
 			colValue: "25",
 		},
 		{
 			name:     "30 == 30",
-			cond:     &Condition{Column: "age", Op: "=", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: "=", Value: NumberLiteral(30)},
 			colValue: "30",
 			wantRes:  true,
 		},
 		{
 			name:     "25 != 30",
-			cond:     &Condition{Column: "age", Op: "!=", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: "!=", Value: NumberLiteral(30)},
 			colValue: "25",
 			wantRes:  true,
 		},
 		{
 			name:     "30 != 30",
-			cond:     &Condition{Column: "age", Op: "!=", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: "!=", Value: NumberLiteral(30)},
 			colValue: "30",
 		},
 		{
 			name:     "25 <= 30",
-			cond:     &Condition{Column: "age", Op: "<=", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: "<=", Value: NumberLiteral(30)},
 			colValue: "25",
 			wantRes:  true,
 		},
 		{
 			name:     "35 <= 30",
-			cond:     &Condition{Column: "age", Op: "<=", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: "<=", Value: NumberLiteral(30)},
 			colValue: "35",
 		},
 		{
 			name:     "35 >= 30",
-			cond:     &Condition{Column: "age", Op: ">=", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: ">=", Value: NumberLiteral(30)},
 			colValue: "35",
 			wantRes:  true,
 		},
 		{
 			name:     "25 >= 30",
-			cond:     &Condition{Column: "age", Op: ">=", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: ">=", Value: NumberLiteral(30)},
 			colValue: "25",
 		},
 
 		{
 			name:     "25 < 30",
-			cond:     &Condition{Column: "age", Op: "<", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: "<", Value: NumberLiteral(30)},
 			colValue: "25",
 			wantRes:  true,
 		},
 		{
 			name:     "35 < 30",
-			cond:     &Condition{Column: "age", Op: "<", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: "<", Value: NumberLiteral(30)},
 			colValue: "35",
 		},
 
 		{
 			name:     "35 > 30",
-			cond:     &Condition{Column: "age", Op: ">", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: ">", Value: NumberLiteral(30)},
 			colValue: "35",
 			wantRes:  true,
 		},
 		{
 			name:     "25 > 30",
-			cond:     &Condition{Column: "age", Op: ">", ValueType: TypeNumber, Value: float64(30)},
+			cond:     &Condition{Column: "age", Op: ">", Value: NumberLiteral(30)},
 			colValue: "25",
 		},
 	}
@@ -152,70 +149,70 @@ func TestStringCondition_CheckCondition(t *testing.T) {
 	}{
 		{
 			name:     "abcd == abc",
-			cond:     &Condition{Column: "str", Op: "=", ValueType: TypeString, Value: "abc"},
+			cond:     &Condition{Column: "str", Op: "=", Value: StringLiteral("abc")},
 			colValue: "abcd",
 		},
 		{
 			name:     "abc == abc",
-			cond:     &Condition{Column: "str", Op: "=", ValueType: TypeString, Value: "abc"},
+			cond:     &Condition{Column: "str", Op: "=", Value: StringLiteral("abc")},
 			colValue: "abc",
 			wantRes:  true,
 		},
 		{
 			name:     "abcd != abc",
-			cond:     &Condition{Column: "str", Op: "!=", ValueType: TypeString, Value: "abc"},
+			cond:     &Condition{Column: "str", Op: "!=", Value: StringLiteral("abc")},
 			colValue: "abcd",
 			wantRes:  true,
 		},
 		{
 			name:     "abc != abc",
-			cond:     &Condition{Column: "str", Op: "!=", ValueType: TypeString, Value: "abc"},
+			cond:     &Condition{Column: "str", Op: "!=", Value: StringLiteral("abc")},
 			colValue: "abc",
 		},
 		{
 			name:     "abc <= bcd",
-			cond:     &Condition{Column: "str", Op: "<=", ValueType: TypeString, Value: "bcd"},
+			cond:     &Condition{Column: "str", Op: "<=", Value: StringLiteral("bcd")},
 			colValue: "abc",
 			wantRes:  true,
 		},
 		{
 			name:     "bcd <= abc",
-			cond:     &Condition{Column: "str", Op: "<=", ValueType: TypeString, Value: "abc"},
+			cond:     &Condition{Column: "str", Op: "<=", Value: StringLiteral("abc")},
 			colValue: "bcd",
 		},
 		{
 			name:     "bcd >= abc",
-			cond:     &Condition{Column: "str", Op: ">=", ValueType: TypeString, Value: "abc"},
+			cond:     &Condition{Column: "str", Op: ">=", Value: StringLiteral("abc")},
 			colValue: "bcd",
 			wantRes:  true,
 		},
 		{
 			name:     "abc >= bcd",
-			cond:     &Condition{Column: "str", Op: ">=", ValueType: TypeString, Value: "bcd"},
+			cond:     &Condition{Column: "str", Op: ">=", Value: StringLiteral("bcd")},
 			colValue: "abc",
 		},
 
 		{
 			name:     "bcd < def",
-			cond:     &Condition{Column: "str", Op: "<", ValueType: TypeString, Value: "def"},
+			cond:     &Condition{Column: "str", Op: "<", Value: StringLiteral("def")},
 			colValue: "bcd",
 			wantRes:  true,
 		},
 		{
 			name:     "def < bcd",
-			cond:     &Condition{Column: "str", Op: "<", ValueType: TypeString, Value: "bcd"},
+			cond:     &Condition{Column: "str", Op: "<", Value: StringLiteral("bcd")},
 			colValue: "def",
 		},
 
 		{
 			name:     "def > bcd",
-			cond:     &Condition{Column: "str", Op: ">", ValueType: TypeString, Value: "bcd"},
+			cond:     &Condition{Column: "str", Op: ">", Value: StringLiteral("bcd")},
 			colValue: "def",
 			wantRes:  true,
 		},
 		{
 			name:     "bcd > def",
-			cond:     &Condition{Column: "str", Op: ">", ValueType: TypeString, Value: "def"},
+			cond:     &Condition{Column: "str", Op: ">", Value: StringLiteral("def")},
 			colValue: "bcd",
 		},
 	}
@@ -230,6 +227,37 @@ func TestStringCondition_CheckCondition(t *testing.T) {
 	}
 }
 
+func TestCondition_CheckTimeCondition(t *testing.T) {
+	early := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		op      ComparisonOperator
+		value   time.Time
+		wantRes bool
+	}{
+		{op: EqualOperator, value: early, wantRes: true},
+		{op: EqualOperator, value: late},
+		{op: NotEqualOperator, value: late, wantRes: true},
+		{op: LessOperator, value: early},
+		{op: LessOrEqualOperator, value: early, wantRes: true},
+		{op: GreaterOperator, value: late, wantRes: true},
+		{op: GreaterOrEqualOperator, value: early, wantRes: true},
+		{op: GreaterOrEqualOperator, value: early.Add(-time.Second)},
+	}
+
+	for _, tt := range tests {
+		cond := &Condition{Column: "created", Op: tt.op, Value: StringLiteral("2021-01-01")}
+		res, err := cond.CheckTimeCondition(tt.value, early)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.wantRes, res, fmt.Sprintf("%s %s", tt.value, tt.op))
+	}
+
+	cond := &Condition{Column: "created", Op: "==", Value: StringLiteral("2021-01-01")}
+	_, err := cond.CheckTimeCondition(early, early)
+	assert.ErrorIs(t, err, ErrUnknownComparisonOperator)
+}
+
 func TestConditionError_CheckCondition(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -239,40 +267,28 @@ func TestConditionError_CheckCondition(t *testing.T) {
 	}{
 		{
 			name:     "Unknown value type",
-			cond:     &Condition{Column: "age", Op: ">", ValueType: ValueType(50), Value: float64(25)},
+			cond:     &Condition{Column: "age", Op: ">", Value: nil},
 			colValue: "25",
 			wantErr:  ErrUnknownValueType,
 		},
-		{
-			name:     "can't cast interface to float64",
-			cond:     &Condition{Column: "age", Op: ">", ValueType: TypeNumber, Value: "twenty five"},
-			colValue: "25",
-			wantErr:  ErrCastInterfaceToFloat64,
-		},
 		{
 			name:     "can't convert string to float64",
-			cond:     &Condition{Column: "age", Op: ">", ValueType: TypeNumber, Value: float64(25)},
+			cond:     &Condition{Column: "age", Op: ">", Value: NumberLiteral(25)},
 			colValue: "twenty five",
 			wantErr:  ErrConvertToFloat64,
 		},
 		{
 			name:     "unknown number comparison operator",
-			cond:     &Condition{Column: "age", Op: "==", ValueType: TypeNumber, Value: float64(25)},
+			cond:     &Condition{Column: "age", Op: "==", Value: NumberLiteral(25)},
 			colValue: "25",
 			wantErr:  ErrUnknownComparisonOperator,
 		},
 		{
 			name:     "unknown string comparison operator",
-			cond:     &Condition{Column: "birthDate", Op: "==", ValueType: TypeString, Value: "2021-01-01"},
+			cond:     &Condition{Column: "birthDate", Op: "==", Value: StringLiteral("2021-01-01")},
 			colValue: "2021-01-01",
 			wantErr:  ErrUnknownComparisonOperator,
 		},
-		{
-			name:     "can't cast interface to string",
-			cond:     &Condition{Column: "age", Op: "==", ValueType: TypeString, Value: float64(25)},
-			colValue: "2021-01-01",
-			wantErr:  ErrCastInterfaceToString,
-		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,48 @@
+package csvquery
+
+import (
+	"strings"
+)
+
+const (
+	// ExplainKeyword returns EXPLAIN keyword.
+	ExplainKeyword keyword = "EXPLAIN"
+	// AnalyzeKeyword returns ANALYZE keyword.
+	AnalyzeKeyword keyword = "ANALYZE"
+)
+
+// ExplainStatement describes EXPLAIN query statement.
+type ExplainStatement struct {
+	// Query is the explained query string, it's parsed by the executor.
+	Query string
+	// Analyze is true if the query is executed to collect statistics.
+	Analyze bool
+}
+
+func (*ExplainStatement) statementNode() {}
+
+// String returns the statement as a query string.
+func (s *ExplainStatement) String() string {
+	if s.Analyze {
+		return string(ExplainKeyword) + " " + string(AnalyzeKeyword) + " " + s.Query
+	}
+
+	return string(ExplainKeyword) + " " + s.Query
+}
+
+// parseExplainStatement parses EXPLAIN statement which starts with the keyword.
+func parseExplainStatement(query string) (Statement, error) {
+	explained := strings.TrimSpace(query[len(ExplainKeyword):])
+
+	var analyze bool
+	if hasKeywordPrefix(explained, AnalyzeKeyword) {
+		analyze = true
+		explained = strings.TrimSpace(explained[len(AnalyzeKeyword):])
+	}
+
+	if explained == "" {
+		return nil, ErrIncorrectQuery
+	}
+
+	return &ExplainStatement{Query: explained, Analyze: analyze}, nil
+}
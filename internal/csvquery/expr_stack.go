@@ -0,0 +1,23 @@
+package csvquery
+
+// ExprStack contains a stack of where statement expressions.
+type ExprStack struct {
+	values []Expr
+}
+
+// Push adds an expression to the stack.
+func (s *ExprStack) Push(value Expr) {
+	s.values = append(s.values, value)
+}
+
+// Pop pops the expression off the end of the stack
+// and ErrPopEmptyStack error if the stack is empty.
+func (s *ExprStack) Pop() (Expr, error) {
+	if len(s.values) == 0 {
+		return nil, ErrPopEmptyStack
+	}
+
+	lastValue := s.values[len(s.values)-1]
+	s.values = s.values[:len(s.values)-1]
+	return lastValue, nil
+}
@@ -0,0 +1,38 @@
+package csvquery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExprStack_Push(t *testing.T) {
+	stack := ExprStack{}
+
+	expr1 := &Condition{Column: "age", Op: "<", Value: NumberLiteral(30)}
+	expr2 := &Condition{Column: "name", Op: "=", Value: StringLiteral("Bob")}
+
+	stack.Push(expr1)
+	stack.Push(expr2)
+
+	assert.Equal(t, []Expr{expr1, expr2}, stack.values)
+}
+
+func TestExprStack_Pop(t *testing.T) {
+	stack := ExprStack{}
+
+	expr, err := stack.Pop()
+	assert.Equal(t, ErrPopEmptyStack, err)
+	assert.Empty(t, expr)
+
+	expr1 := &Condition{Column: "age", Op: "<", Value: NumberLiteral(30)}
+	expr2 := &Condition{Column: "name", Op: "=", Value: StringLiteral("Bob")}
+
+	stack.Push(expr1)
+	stack.Push(expr2)
+
+	expr, err = stack.Pop()
+	assert.NoError(t, err)
+	assert.Equal(t, expr2, expr)
+	assert.Equal(t, []Expr{expr1}, stack.values)
+}
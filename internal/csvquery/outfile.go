@@ -0,0 +1,338 @@
+package csvquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/phpCoder88/csv-searcher/internal/output"
+)
+
+const (
+	// CopyKeyword returns COPY keyword.
+	CopyKeyword keyword = "COPY"
+	// ToKeyword returns TO keyword.
+	ToKeyword keyword = "TO"
+	// IntoKeyword returns INTO keyword.
+	IntoKeyword keyword = "INTO"
+	// OutFileKeyword returns OUTFILE keyword.
+	OutFileKeyword keyword = "OUTFILE"
+	// WithKeyword returns WITH keyword.
+	WithKeyword keyword = "WITH"
+)
+
+// Output file options.
+const (
+	delimiterOption = "DELIMITER"
+	headerOption    = "HEADER"
+	quoteOption     = "QUOTE"
+)
+
+// ErrIncorrectOutFile returns error if query string has incorrect output file clause.
+var ErrIncorrectOutFile = fmt.Errorf("%w: incorrect output file clause", ErrIncorrectQuery)
+
+// OutFile describes the file which the query result is written to.
+type OutFile struct {
+	Path string
+	// Delimiter is the field delimiter, zero value means the configured table delimiter.
+	Delimiter rune
+	Header    bool
+	Quote     output.QuoteMode
+}
+
+// String returns the output file clause as it is written in a query.
+func (f *OutFile) String() string {
+	var options []string
+	if f.Delimiter != 0 {
+		options = append(options, delimiterOption+" "+StringLiteral(f.Delimiter).String())
+	}
+	if !f.Header {
+		options = append(options, headerOption+" false")
+	}
+	if f.Quote != output.QuoteMinimal {
+		options = append(options, quoteOption+" "+string(f.Quote))
+	}
+
+	clause := string(IntoKeyword) + " " + string(OutFileKeyword) + " " + StringLiteral(f.Path).String()
+	if len(options) > 0 {
+		clause += " " + string(WithKeyword) + " (" + strings.Join(options, ", ") + ")"
+	}
+
+	return clause
+}
+
+// parseCopyStatement unwraps COPY (query) TO 'file' [WITH (options)] statement.
+func (q *Query) parseCopyStatement() error {
+	if !hasKeywordPrefix(q.query, CopyKeyword) {
+		return nil
+	}
+
+	rest := strings.TrimSpace(q.query[len(CopyKeyword):])
+	if !strings.HasPrefix(rest, "(") {
+		q.logger.Error("Not found query in COPY statement")
+		return ErrIncorrectQuery
+	}
+
+	end := findClosingBracket(rest)
+	if end == -1 {
+		q.logger.Error(ErrIncorrectBracketPosition.Error())
+		return ErrIncorrectBracketPosition
+	}
+
+	inner := strings.TrimSpace(rest[1:end])
+	rest = strings.TrimSpace(rest[end+1:])
+	if !hasKeywordPrefix(rest, ToKeyword) {
+		q.logger.Error("Not found TO in COPY statement")
+		return ErrIncorrectOutFile
+	}
+
+	outFile, err := parseOutFile(strings.TrimSpace(rest[len(ToKeyword):]))
+	if err != nil {
+		q.logger.Error(err.Error())
+		return ErrIncorrectOutFile
+	}
+
+	q.query = inner
+	q.Into = outFile
+
+	return nil
+}
+
+// parseIntoStatement cuts INTO OUTFILE 'file' [WITH (options)] clause off the end of the query.
+func (q *Query) parseIntoStatement() error {
+	pos := findKeyword(q.query, IntoKeyword)
+	if pos == -1 {
+		return nil
+	}
+
+	if q.Into != nil {
+		q.logger.Error("Output file is given twice")
+		return ErrIncorrectOutFile
+	}
+
+	rest := strings.TrimSpace(q.query[pos+len(IntoKeyword):])
+	if !hasKeywordPrefix(rest, OutFileKeyword) {
+		q.logger.Error("Not found OUTFILE in INTO clause")
+		return ErrIncorrectOutFile
+	}
+
+	outFile, err := parseOutFile(strings.TrimSpace(rest[len(OutFileKeyword):]))
+	if err != nil {
+		q.logger.Error(err.Error())
+		return ErrIncorrectOutFile
+	}
+
+	q.query = strings.TrimSpace(q.query[:pos])
+	q.Into = outFile
+
+	return nil
+}
+
+// parseOutFile parses 'file' [WITH (options)].
+func parseOutFile(clause string) (*OutFile, error) {
+	path, rest, err := readStringLiteral(clause)
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf("%w: empty file name", ErrIncorrectOutFile)
+	}
+
+	outFile := &OutFile{
+		Path:   path,
+		Header: true,
+		Quote:  output.QuoteMinimal,
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return outFile, nil
+	}
+
+	if !hasKeywordPrefix(rest, WithKeyword) {
+		return nil, fmt.Errorf("%w: unexpected '%s'", ErrIncorrectOutFile, rest)
+	}
+
+	rest = strings.TrimSpace(rest[len(WithKeyword):])
+	if !strings.HasPrefix(rest, "(") || findClosingBracket(rest) != len(rest)-1 {
+		return nil, fmt.Errorf("%w: options must be enclosed in brackets", ErrIncorrectOutFile)
+	}
+
+	for _, option := range splitOutside(rest[1:len(rest)-1], ',') {
+		err = outFile.setOption(strings.TrimSpace(option))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return outFile, nil
+}
+
+func (f *OutFile) setOption(option string) error {
+	nameEnd := strings.IndexRune(option, ' ')
+	if nameEnd == -1 {
+		return fmt.Errorf("%w: option '%s' has no value", ErrIncorrectOutFile, option)
+	}
+
+	name := strings.ToUpper(option[:nameEnd])
+	value := strings.TrimSpace(option[nameEnd+1:])
+	if strings.HasPrefix(value, "'") || strings.HasPrefix(value, `"`) {
+		unquoted, rest, err := readStringLiteral(value)
+		if err != nil || rest != "" {
+			return fmt.Errorf("%w: incorrect value of option '%s'", ErrIncorrectOutFile, name)
+		}
+		value = unquoted
+	}
+
+	switch name {
+	case delimiterOption:
+		if value == `\t` {
+			value = "\t"
+		}
+		if utf8.RuneCountInString(value) != 1 {
+			return fmt.Errorf("%w: delimiter must be one rune", ErrIncorrectOutFile)
+		}
+		f.Delimiter, _ = utf8.DecodeRuneInString(value)
+
+	case headerOption:
+		header, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%w: incorrect header value '%s'", ErrIncorrectOutFile, value)
+		}
+		f.Header = header
+
+	case quoteOption:
+		quote, err := output.ParseQuoteMode(value)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrIncorrectOutFile, err)
+		}
+		f.Quote = quote
+
+	default:
+		return fmt.Errorf("%w: unknown option '%s'", ErrIncorrectOutFile, name)
+	}
+
+	return nil
+}
+
+// hasKeywordPrefix returns true if str starts with the case insensitive keyword followed by a space or a bracket.
+func hasKeywordPrefix(str string, kw keyword) bool {
+	if len(str) < len(kw) || !strings.EqualFold(str[:len(kw)], string(kw)) {
+		return false
+	}
+
+	return len(str) == len(kw) || str[len(kw)] == ' ' || str[len(kw)] == '('
+}
+
+// findKeyword returns position of the first keyword which is outside of string literals and brackets
+// and separated by spaces, or -1 if there is no keyword.
+func findKeyword(str string, kw keyword) int {
+	var quote byte
+	var depth int
+
+	for i := 0; i < len(str); i++ {
+		char := str[i]
+		switch {
+		case quote != 0 && char == '\\':
+			i++
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"':
+			quote = char
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		case depth == 0 && (i == 0 || str[i-1] == ' ') && hasKeywordPrefix(str[i:], kw):
+			return i
+		}
+	}
+
+	return -1
+}
+
+// findClosingBracket returns position of the bracket which closes the opening bracket at the start of str
+// or -1 if there is no such bracket.
+func findClosingBracket(str string) int {
+	var quote byte
+	var depth int
+
+	for i := 0; i < len(str); i++ {
+		char := str[i]
+		switch {
+		case quote != 0 && char == '\\':
+			i++
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"':
+			quote = char
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// splitOutside splits str by sep which is outside of string literals.
+func splitOutside(str string, sep byte) []string {
+	var parts []string
+	var quote byte
+	start := 0
+
+	for i := 0; i < len(str); i++ {
+		char := str[i]
+		switch {
+		case quote != 0 && char == '\\':
+			i++
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"':
+			quote = char
+		case char == sep:
+			parts = append(parts, str[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, str[start:])
+}
+
+// readStringLiteral reads the quoted string at the start of str and returns it with the rest of str.
+func readStringLiteral(str string) (value, rest string, err error) {
+	if str == "" || (str[0] != '\'' && str[0] != '"') {
+		return "", "", fmt.Errorf("%w: string literal expected", ErrIncorrectOutFile)
+	}
+
+	quote := str[0]
+	var b strings.Builder
+	for i := 1; i < len(str); i++ {
+		char := str[i]
+		if char == '\\' && i+1 < len(str) && str[i+1] == quote {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+
+		if char == quote {
+			return b.String(), str[i+1:], nil
+		}
+
+		b.WriteByte(char)
+	}
+
+	return "", "", fmt.Errorf("%w: unterminated string literal", ErrIncorrectOutFile)
+}
@@ -0,0 +1,82 @@
+package csvquery
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/phpCoder88/csv-searcher/internal/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_OutFile(t *testing.T) {
+	tests := []struct {
+		query       string
+		wantSelect  Columns
+		wantWhere   Expr
+		wantOutFile *OutFile
+	}{
+		{
+			query:       "select * from users into outfile 'result.csv'",
+			wantSelect:  Columns{"*"},
+			wantOutFile: &OutFile{Path: "result.csv", Header: true, Quote: output.QuoteMinimal},
+		},
+		{
+			query:      "select name from users where name = 'into' INTO OUTFILE \"it's.csv\" with (delimiter ';', header false, quote 'all')",
+			wantSelect: Columns{"name"},
+			wantWhere:  &Condition{Column: "name", Op: "=", Value: StringLiteral("into")},
+			wantOutFile: &OutFile{
+				Path:      "it's.csv",
+				Delimiter: ';',
+				Quote:     output.QuoteAll,
+			},
+		},
+		{
+			query:       "copy (select name from users where (age > 30)) to 'result.csv' WITH (DELIMITER '\\t', QUOTE none)",
+			wantSelect:  Columns{"name"},
+			wantWhere:   &Condition{Column: "age", Op: ">", Value: NumberLiteral(30)},
+			wantOutFile: &OutFile{Path: "result.csv", Delimiter: '\t', Header: true, Quote: output.QuoteNone},
+		},
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query := NewQuery(tt.query, logger)
+			assert.NoError(t, query.Parse())
+			assert.Equal(t, tt.wantSelect, query.Select)
+			assert.Equal(t, Tables{"users"}, query.From)
+			assert.Equal(t, tt.wantWhere, query.Where)
+			assert.Equal(t, tt.wantOutFile, query.Into)
+
+			reparsed := NewQuery(query.Statement().String(), logger)
+			assert.NoError(t, reparsed.Parse())
+			assert.Equal(t, query.Statement(), reparsed.Statement())
+		})
+	}
+}
+
+func TestQuery_OutFileErrors(t *testing.T) {
+	tests := []string{
+		"select * from users into 'result.csv'",
+		"select * from users into outfile result.csv",
+		"select * from users into outfile ''",
+		"select * from users into outfile 'result.csv' with delimiter ';'",
+		"select * from users into outfile 'result.csv' with (delimiter ';;')",
+		"select * from users into outfile 'result.csv' with (header maybe)",
+		"select * from users into outfile 'result.csv' with (quote sometimes)",
+		"select * from users into outfile 'result.csv' with (encoding 'utf8')",
+		"copy select * from users to 'result.csv'",
+		"copy (select * from users to 'result.csv'",
+		"copy (select * from users) 'result.csv'",
+		"copy (select * from users into outfile 'a.csv') to 'result.csv'",
+	}
+	logger := zaptest.NewLogger(t)
+
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			query := NewQuery(tt, logger)
+			assert.ErrorIs(t, query.Parse(), ErrIncorrectQuery)
+		})
+	}
+}
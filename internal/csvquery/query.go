@@ -7,8 +7,6 @@ import (
 	"strings"
 
 	"go.uber.org/zap"
-
-	"github.com/phpCoder88/csv-searcher/internal/structs"
 )
 
 // keyword describes sql keyword type.
@@ -27,6 +25,22 @@ const (
 	OrKeyword keyword = "OR"
 )
 
+// Keywords returns keywords of the query language.
+func Keywords() []string {
+	keywords := []keyword{
+		SelectKeyword, FromKeyword, WhereKeyword, AndKeyword, OrKeyword,
+		CopyKeyword, ToKeyword, IntoKeyword, OutFileKeyword, WithKeyword,
+		ShowKeyword, TablesKeyword, DescribeKeyword, DescKeyword, ExplainKeyword, AnalyzeKeyword,
+	}
+
+	result := make([]string, 0, len(keywords))
+	for _, kw := range keywords {
+		result = append(result, string(kw))
+	}
+
+	return result
+}
+
 // Column describes table column.
 type Column string
 
@@ -59,7 +73,8 @@ type Query struct {
 	Select      Columns
 	StarColumn  bool
 	From        Tables
-	Where       *structs.Tree
+	Where       Expr
+	Into        *OutFile
 	UsedColumns QueryColumns
 	cursor      int
 	logger      *zap.Logger
@@ -84,7 +99,17 @@ func NewQuery(query string, logger *zap.Logger) *Query {
 
 // Parse parses the sql like query string.
 func (q *Query) Parse() error {
-	err := q.ParseSelectStatement()
+	err := q.parseCopyStatement()
+	if err != nil {
+		return err
+	}
+
+	err = q.parseIntoStatement()
+	if err != nil {
+		return err
+	}
+
+	err = q.ParseSelectStatement()
 	if err != nil {
 		return err
 	}
@@ -104,6 +129,16 @@ func (q *Query) Parse() error {
 	return nil
 }
 
+// Statement returns the syntax tree of the parsed query.
+func (q *Query) Statement() *SelectStatement {
+	return &SelectStatement{
+		Select: q.Select,
+		From:   q.From,
+		Where:  q.Where,
+		Into:   q.Into,
+	}
+}
+
 // ParseSelectStatement parses select statement.
 func (q *Query) ParseSelectStatement() error {
 	if !strings.HasPrefix(strings.ToUpper(q.query[q.cursor:]), string(SelectKeyword)+" ") {
@@ -157,7 +192,7 @@ func (q *Query) ParseFromStatement() error {
 
 	q.From = make(Tables, len(tables))
 	for i, table := range tables {
-		q.From[i] = Table(table)
+		q.From[i] = Table(unquoteTable(table))
 	}
 
 	return nil
@@ -254,6 +289,22 @@ func (q *Query) parseSelectFromStatement() ([]string, error) {
 	return tokens, nil
 }
 
+// unquoteTable removes quotes around the table name, glob patterns like 'logs/2021-*.csv' are usually quoted.
+// The file and the sheet of a workbook table like 'report.xlsx'!Sheet1 are unquoted separately.
+func unquoteTable(name string) string {
+	if len(name) >= 2 && (name[0] == '\'' || name[0] == '"') {
+		if end := strings.Index(name[1:], string(name[0])+"!"); end != -1 {
+			return name[1:end+1] + "!" + unquoteTable(name[end+3:])
+		}
+	}
+
+	if len(name) >= 2 && (name[0] == '\'' || name[0] == '"') && name[len(name)-1] == name[0] {
+		return name[1 : len(name)-1]
+	}
+
+	return name
+}
+
 func (q *Query) mergeColumns(whereColumns map[Column]int) {
 	for column := range whereColumns {
 		q.UsedColumns.add(column)
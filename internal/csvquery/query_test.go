@@ -5,12 +5,11 @@ import (
 
 	"go.uber.org/zap/zaptest"
 
-	"github.com/phpCoder88/csv-searcher/internal/structs"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestQuery(t *testing.T) {
-	cond1 := &Condition{Column: "age", Op: "=", ValueType: TypeNumber, Value: float64(33)}
+	cond1 := &Condition{Column: "age", Op: "=", Value: NumberLiteral(33)}
 
 	tests := []struct {
 		query      string
@@ -27,13 +26,33 @@ func TestQuery(t *testing.T) {
 				cursor:      19,
 			},
 		},
+		{
+			query: "select _file, name from 'logs/2021-*.csv', \"logs/archive\"",
+			wantResult: &Query{
+				query:       "select _file, name from 'logs/2021-*.csv', \"logs/archive\"",
+				Select:      Columns{"_file", "name"},
+				From:        Tables{"logs/2021-*.csv", "logs/archive"},
+				UsedColumns: QueryColumns{"_file", "name"},
+				cursor:      57,
+			},
+		},
+		{
+			query: `select name from 'report.xlsx'!Sheet1, "q1.xlsx"!"Totals"`,
+			wantResult: &Query{
+				query:       `select name from 'report.xlsx'!Sheet1, "q1.xlsx"!"Totals"`,
+				Select:      Columns{"name"},
+				From:        Tables{"report.xlsx!Sheet1", "q1.xlsx!Totals"},
+				UsedColumns: QueryColumns{"name"},
+				cursor:      57,
+			},
+		},
 		{
 			query: "select name,age from users where age = 33",
 			wantResult: &Query{
 				query:       "select name,age from users where age = 33",
 				Select:      Columns{"name", "age"},
 				From:        Tables{"users"},
-				Where:       structs.NewTree(cond1, nil, nil),
+				Where:       cond1,
 				UsedColumns: QueryColumns{"name", "age"},
 				cursor:      33,
 			},
@@ -53,9 +72,7 @@ func TestQuery(t *testing.T) {
 			assert.Equal(t, tt.wantResult.cursor, query.cursor)
 			assert.Equal(t, tt.wantResult.UsedColumns, query.UsedColumns)
 
-			assert.Condition(t, func() bool {
-				return sameTree(tt.wantResult.Where, query.Where)
-			})
+			assert.Equal(t, tt.wantResult.Where, query.Where)
 		})
 	}
 }
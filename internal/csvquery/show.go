@@ -0,0 +1,65 @@
+package csvquery
+
+import (
+	"strings"
+)
+
+const (
+	// ShowKeyword returns SHOW keyword.
+	ShowKeyword keyword = "SHOW"
+	// TablesKeyword returns TABLES keyword.
+	TablesKeyword keyword = "TABLES"
+	// DescribeKeyword returns DESCRIBE keyword.
+	DescribeKeyword keyword = "DESCRIBE"
+	// DescKeyword returns DESC keyword, the short form of DESCRIBE.
+	DescKeyword keyword = "DESC"
+)
+
+// ShowTablesStatement describes SHOW TABLES statement.
+type ShowTablesStatement struct{}
+
+// DescribeStatement describes DESCRIBE table statement.
+type DescribeStatement struct {
+	Table Table
+}
+
+func (*ShowTablesStatement) statementNode() {}
+func (*DescribeStatement) statementNode()   {}
+
+// String returns the statement as a query string.
+func (*ShowTablesStatement) String() string {
+	return string(ShowKeyword) + " " + string(TablesKeyword)
+}
+
+// String returns the statement as a query string.
+func (s *DescribeStatement) String() string {
+	return string(DescribeKeyword) + " " + s.Table.String()
+}
+
+// ParseInfoStatement parses the statements which describe tables or queries instead of executing queries.
+// It returns nil statement if the query is not such a statement.
+func ParseInfoStatement(query string) (Statement, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	switch strings.ToUpper(words[0]) {
+	case string(ExplainKeyword):
+		return parseExplainStatement(strings.TrimSpace(query))
+
+	case string(ShowKeyword):
+		if len(words) != 2 || !strings.EqualFold(words[1], string(TablesKeyword)) {
+			return nil, ErrIncorrectQuery
+		}
+		return &ShowTablesStatement{}, nil
+
+	case string(DescribeKeyword), string(DescKeyword):
+		if len(words) != 2 {
+			return nil, ErrIncorrectQuery
+		}
+		return &DescribeStatement{Table: Table(words[1])}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,46 @@
+package csvquery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInfoStatement(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    Statement
+		wantErr error
+	}{
+		{query: "SHOW TABLES", want: &ShowTablesStatement{}},
+		{query: " show  tables ", want: &ShowTablesStatement{}},
+		{query: "describe users.csv", want: &DescribeStatement{Table: "users.csv"}},
+		{query: "DESC users.csv", want: &DescribeStatement{Table: "users.csv"}},
+		{query: "explain  select * from users.csv ", want: &ExplainStatement{Query: "select * from users.csv"}},
+		{query: "EXPLAIN", wantErr: ErrIncorrectQuery},
+		{query: "explain analyze select 1", want: &ExplainStatement{Query: "select 1", Analyze: true}},
+		{query: "EXPLAIN ANALYZE", wantErr: ErrIncorrectQuery},
+		{query: "explain analyzed.csv", want: &ExplainStatement{Query: "analyzed.csv"}},
+		{query: "select * from users.csv"},
+		{query: ""},
+		{query: "show users.csv", wantErr: ErrIncorrectQuery},
+		{query: "show tables users.csv", wantErr: ErrIncorrectQuery},
+		{query: "describe", wantErr: ErrIncorrectQuery},
+		{query: "describe users.csv roles.csv", wantErr: ErrIncorrectQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			statement, err := ParseInfoStatement(tt.query)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, statement)
+		})
+	}
+}
+
+func TestInfoStatement_String(t *testing.T) {
+	assert.Equal(t, "SHOW TABLES", (&ShowTablesStatement{}).String())
+	assert.Equal(t, "DESCRIBE users.csv", (&DescribeStatement{Table: "users.csv"}).String())
+	assert.Equal(t, "EXPLAIN select 1", (&ExplainStatement{Query: "select 1"}).String())
+	assert.Equal(t, "EXPLAIN ANALYZE select 1", (&ExplainStatement{Query: "select 1", Analyze: true}).String())
+}
@@ -36,7 +36,7 @@ func NewWhereParser(where string, logger *zap.Logger) *WhereParser {
 }
 
 // Parse parses the where statement.
-func (p *WhereParser) Parse() (map[Column]int, *structs.Tree, error) {
+func (p *WhereParser) Parse() (map[Column]int, Expr, error) {
 	err := p.processWhereStmt()
 	if err != nil {
 		return nil, nil, err
@@ -104,7 +104,7 @@ func (p *WhereParser) findCondition() (*Condition, error) {
 
 	p.skipSpace()
 
-	value, valueType, err := p.extractConditionValue()
+	value, err := p.extractConditionValue()
 	if err != nil {
 		return nil, err
 	}
@@ -112,10 +112,9 @@ func (p *WhereParser) findCondition() (*Condition, error) {
 	p.skipSpace()
 
 	return &Condition{
-		Column:    column,
-		Op:        op,
-		Value:     value,
-		ValueType: valueType,
+		Column: column,
+		Op:     op,
+		Value:  value,
 	}, nil
 }
 
@@ -159,26 +158,24 @@ func (p *WhereParser) extractConditionOperator() (ComparisonOperator, error) {
 	return ComparisonOperator(op), nil
 }
 
-func (p *WhereParser) extractConditionValue() (value interface{}, valueType ValueType, err error) {
+func (p *WhereParser) extractConditionValue() (Literal, error) {
 	if strings.HasPrefix(p.where[p.cursor:], "'") || strings.HasPrefix(p.where[p.cursor:], "\"") {
 		// поиск строки
-		value, err = p.extractStringConditionValue()
+		value, err := p.extractStringConditionValue()
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 
-		valueType = TypeString
-	} else {
-		// поиск числа и преобразование строки в число
-		value, err = p.extractNumberConditionValue()
-		if err != nil {
-			return nil, 0, err
-		}
+		return StringLiteral(value), nil
+	}
 
-		valueType = TypeNumber
+	// поиск числа и преобразование строки в число
+	value, err := p.extractNumberConditionValue()
+	if err != nil {
+		return nil, err
 	}
 
-	return value, valueType, nil
+	return NumberLiteral(value), nil
 }
 
 func (p *WhereParser) extractStringConditionValue() (string, error) {
@@ -351,18 +348,15 @@ func (p *WhereParser) processBinaryOperator() error {
 	return nil
 }
 
-func (p *WhereParser) parseToTree(postfix []string) *structs.Tree {
-	stack := structs.TreeStack{}
+func (p *WhereParser) parseToTree(postfix []string) Expr {
+	stack := ExprStack{}
 	for _, item := range postfix {
 		if !IsOperator(item) {
-			node := structs.NewTree(p.condMap[item], nil, nil)
-			stack.Push(node)
+			stack.Push(p.condMap[item])
 		} else {
 			right, _ := stack.Pop()
 			left, _ := stack.Pop()
-			newTree := structs.NewTree(item, left, right)
-
-			stack.Push(newTree)
+			stack.Push(&BinaryExpr{Op: LogicalOperator(item), Left: left, Right: right})
 		}
 	}
 
@@ -6,20 +6,18 @@ import (
 
 	"go.uber.org/zap/zaptest"
 
-	"github.com/phpCoder88/csv-searcher/internal/structs"
-
 	"github.com/stretchr/testify/assert"
 )
 
 func TestWhereParser(t *testing.T) {
-	cond1 := &Condition{Column: "age", Op: "<=", ValueType: TypeNumber, Value: float64(54)}
-	cond2 := &Condition{Column: "country", Op: "=", ValueType: TypeString, Value: "Europe"}
-	cond3 := &Condition{Column: "company", Op: "=", ValueType: TypeString, Value: `OOO "Company Name"`}
+	cond1 := &Condition{Column: "age", Op: "<=", Value: NumberLiteral(54)}
+	cond2 := &Condition{Column: "country", Op: "=", Value: StringLiteral("Europe")}
+	cond3 := &Condition{Column: "company", Op: "=", Value: StringLiteral(`OOO "Company Name"`)}
 
 	tests := []struct {
 		where       string
 		wantError   error
-		wantResult  *structs.Tree
+		wantResult  Expr
 		wantColumns map[Column]int
 	}{
 		{
@@ -65,25 +63,25 @@ func TestWhereParser(t *testing.T) {
 		{
 			where:       "age <= 54",
 			wantError:   nil,
-			wantResult:  structs.NewTree(cond1, nil, nil),
+			wantResult:  cond1,
 			wantColumns: map[Column]int{"age": 0},
 		},
 		{
 			where:       `company = "OOO \"Company Name\""`,
 			wantError:   nil,
-			wantResult:  structs.NewTree(cond3, nil, nil),
+			wantResult:  cond3,
 			wantColumns: map[Column]int{"company": 0},
 		},
 		{
 			where:       "age <= 54 or country = 'Europe'",
 			wantError:   nil,
-			wantResult:  structs.NewTree("OR", structs.NewTree(cond1, nil, nil), structs.NewTree(cond2, nil, nil)),
+			wantResult:  &BinaryExpr{Op: OrOperator, Left: cond1, Right: cond2},
 			wantColumns: map[Column]int{"age": 0, "country": 0},
 		},
 		{
 			where:       "age <= 54 or (country = 'Europe')",
 			wantError:   nil,
-			wantResult:  structs.NewTree("OR", structs.NewTree(cond1, nil, nil), structs.NewTree(cond2, nil, nil)),
+			wantResult:  &BinaryExpr{Op: OrOperator, Left: cond1, Right: cond2},
 			wantColumns: map[Column]int{"age": 0, "country": 0},
 		},
 	}
@@ -103,42 +101,7 @@ func TestWhereParser(t *testing.T) {
 			}
 
 			assert.Equal(t, tt.wantColumns, columns)
-			assert.Condition(t, func() bool {
-				return sameTree(tt.wantResult, tree)
-			})
+			assert.Equal(t, tt.wantResult, tree)
 		})
 	}
 }
-
-func sameTree(expected, actual *structs.Tree) bool {
-	if expected == nil && actual == nil {
-		return true
-	}
-
-	if (expected == nil && actual != nil) || (expected != nil && actual == nil) {
-		return false
-	}
-
-	res := sameTree(expected.LeftChild(), actual.LeftChild())
-	if !res {
-		return res
-	}
-
-	expVal, expOk := expected.GetValue().(string)
-	actVal, actOk := actual.GetValue().(string)
-	if (expOk != actOk) || (expOk && expVal != actVal) {
-		return false
-	}
-
-	if !expOk {
-		expVal, expOk := expected.GetValue().(*Condition)
-		actVal, actOk := actual.GetValue().(*Condition)
-
-		if (expOk != actOk) || (expOk && *expVal != *actVal) {
-			return false
-		}
-	}
-
-	res = sameTree(expected.RightChild(), actual.RightChild())
-	return res
-}
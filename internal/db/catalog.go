@@ -0,0 +1,98 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+// ErrIncorrectDate indicates that a value of a date column can't be parsed.
+var ErrIncorrectDate = errors.New("incorrect date")
+
+// loadCatalog reads the catalog file of the table location, it returns nil catalog if there is no file.
+func loadCatalog(connector TableConnector, tableLocation string) (*catalog.Catalog, error) {
+	catalogPath := path.Join(tableLocation, catalog.FileName)
+	if !connector.Exists(catalogPath) {
+		return nil, nil
+	}
+
+	file, err := connector.GetReader(catalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", catalog.ErrIncorrectCatalog, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return catalog.Parse(file)
+}
+
+// quoteSwapReader swaps the table quote character and the double quote which is the only quote of csv.Reader.
+// Decoded fields are swapped back by Table.normalizeRow.
+type quoteSwapReader struct {
+	reader io.Reader
+	quote  byte
+}
+
+func (r *quoteSwapReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	for i := 0; i < n; i++ {
+		switch p[i] {
+		case r.quote:
+			p[i] = '"'
+		case '"':
+			p[i] = r.quote
+		}
+	}
+
+	return n, err
+}
+
+// swapQuotes swaps the quote character and the double quote in the fields.
+func swapQuotes(fields []string, quote byte) {
+	for i, field := range fields {
+		if !strings.ContainsAny(field, string([]byte{quote, '"'})) {
+			continue
+		}
+
+		fields[i] = strings.Map(func(r rune) rune {
+			switch r {
+			case rune(quote):
+				return '"'
+			case '"':
+				return rune(quote)
+			}
+			return r
+		}, field)
+	}
+}
+
+// checkDateCondition checks the condition of the date column declared in the catalog.
+func (t *Table) checkDateCondition(cond *csvquery.Condition, value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+
+	literal, ok := cond.Value.(csvquery.StringLiteral)
+	if !ok {
+		return false, fmt.Errorf("%w: column: %s, condition value %s isn't a date string", ErrIncorrectDate, cond.Column, cond.Value)
+	}
+
+	condDate, ok := t.options.ParseDate(string(literal))
+	if !ok {
+		return false, fmt.Errorf("%w: column: %s, condition value: %s", ErrIncorrectDate, cond.Column, literal)
+	}
+
+	date, ok := t.options.ParseDate(value)
+	if !ok {
+		return false, fmt.Errorf("%w: column: %s, row value: %s", ErrIncorrectDate, cond.Column, value)
+	}
+
+	return cond.CheckTimeCondition(date, condDate)
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/config"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	dir := t.TempDir()
+
+	cat, err := loadCatalog(FileTableConnector{}, dir)
+	assert.NoError(t, err)
+	assert.Nil(t, cat)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, catalog.FileName), []byte("tables:\n  users.csv:\n    delimiter: ';'\n"), 0600))
+	cat, err = loadCatalog(FileTableConnector{}, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, ';', cat.Table("users.csv").DelimiterRune(','))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, catalog.FileName), []byte("tables: ["), 0600))
+	_, err = loadCatalog(FileTableConnector{}, dir)
+	assert.ErrorIs(t, err, catalog.ErrIncorrectCatalog)
+}
+
+func TestExecute_catalogOptions(t *testing.T) {
+	dir := t.TempDir()
+	catalogContent := `tables:
+  users.csv:
+    delimiter: ";"
+    quote: "'"
+    comment: "#"
+    null_values: ["NULL"]
+    columns:
+      born: date
+    date_layouts: ["02.01.2006"]
+`
+	tableContent := "# exported users\nid;name;born\n1;'Smith; John';01.02.1990\n2;\"Bob\";NULL\n3;Ann;15.06.2001\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, catalog.FileName), []byte(catalogContent), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte(tableContent), 0600))
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+	}
+
+	tests := []struct {
+		query string
+		file  string
+		want  string
+	}{
+		{
+			query: "select * from users.csv into outfile 'all.csv'",
+			file:  "all.csv",
+			want:  "id,name,born\n1,Smith; John,01.02.1990\n2,\"\"\"Bob\"\"\",\n3,Ann,15.06.2001\n",
+		},
+		{
+			query: "select name from users.csv where born >= '1995-01-01' into outfile 'young.csv'",
+			file:  "young.csv",
+			want:  "name\nAnn\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			err := Execute(context.Background(), FileTableConnector{}, tt.query, conf, zap.NewNop())
+			assert.NoError(t, err)
+
+			content, err := os.ReadFile(filepath.Join(dir, tt.file))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(content))
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+// tokenSeparators separate words of a statement for completion.
+const tokenSeparators = " \t,()=<>!"
+
+// Completer completes keywords, table names and column names of the statement being typed.
+type Completer struct {
+	connector TableConnector
+	config    *config.Config
+	logger    *zap.Logger
+}
+
+// NewCompleter returns new instance of Completer.
+func NewCompleter(connector TableConnector, conf *config.Config, logger *zap.Logger) *Completer {
+	return &Completer{
+		connector: connector,
+		config:    conf,
+		logger:    logger,
+	}
+}
+
+// Complete returns completions of the word.
+// before is the statement text before the word, after is the statement text after it.
+// Table names are completed after FROM and DESCRIBE, column names are read from tables mentioned in the statement.
+func (c *Completer) Complete(before, word, after string) []string {
+	var candidates []string
+	switch lastKeyword(before) {
+	case string(csvquery.FromKeyword), string(csvquery.DescribeKeyword), string(csvquery.DescKeyword):
+		candidates = append(candidates, c.completeTable(word)...)
+	default:
+		for _, table := range c.mentionedTables(before + " " + after) {
+			candidates = append(candidates, matchPrefix(c.readHeader(table), word, false)...)
+		}
+	}
+
+	candidates = append(candidates, matchPrefix(csvquery.Keywords(), word, true)...)
+	return unique(candidates)
+}
+
+// completeTable returns files and directories of the table location which names start with the word.
+func (c *Completer) completeTable(word string) []string {
+	dir, prefix := path.Split(word)
+
+	infos, err := c.connector.List(path.Join(c.config.TableLocation, dir))
+	if err != nil {
+		c.logger.Debug(err.Error())
+		return nil
+	}
+
+	var result []string
+	for _, info := range infos {
+		name := info.Name()
+		if strings.HasPrefix(name, ".") || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		if info.IsDir() {
+			name += "/"
+		}
+		result = append(result, dir+name)
+	}
+
+	return result
+}
+
+// mentionedTables returns words of the statement which are names of existing tables.
+func (c *Completer) mentionedTables(statement string) []string {
+	var tables []string
+	for _, token := range splitTokens(statement) {
+		if isKeyword(token) || strings.ContainsAny(token, `'"`) {
+			continue
+		}
+
+		file, _ := splitSheet(token)
+		if c.connector.Exists(path.Join(c.config.TableLocation, file)) {
+			tables = append(tables, token)
+		}
+	}
+
+	return unique(tables)
+}
+
+// readHeader reads only the header row of the table or the first row of a table without a header.
+// Table format options of the catalog are applied as for a query.
+func (c *Completer) readHeader(name string) []string {
+	db := NewDB(c.connector, &csvquery.Query{}, c.logger, c.config)
+
+	var err error
+	db.catalog, err = loadCatalog(c.connector, c.config.TableLocation)
+	if err != nil {
+		c.logger.Debug(err.Error())
+		return nil
+	}
+
+	table := NewTable(csvquery.Table(name), db.query, db)
+	source, err := table.connect()
+	if err != nil {
+		c.logger.Debug(err.Error())
+		return nil
+	}
+
+	defer func() {
+		_ = table.connection.Close()
+	}()
+
+	if csvSource, ok := source.(*csvSource); ok {
+		csvSource.reader.FieldsPerRecord = -1
+		csvSource.reader.LazyQuotes = true
+	}
+
+	header, err := source.readHeader()
+	if err != nil {
+		c.logger.Debug(err.Error())
+		return nil
+	}
+
+	return header
+}
+
+// lastKeyword returns the last keyword of the statement in upper case or empty string.
+func lastKeyword(statement string) string {
+	tokens := splitTokens(statement)
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if isKeyword(tokens[i]) {
+			return strings.ToUpper(tokens[i])
+		}
+	}
+
+	return ""
+}
+
+func isKeyword(token string) bool {
+	for _, kw := range csvquery.Keywords() {
+		if strings.EqualFold(token, kw) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitTokens(statement string) []string {
+	return strings.FieldsFunc(statement, func(r rune) bool {
+		return strings.ContainsRune(tokenSeparators, r)
+	})
+}
+
+// matchPrefix returns sorted words which start with the prefix.
+// Keywords are matched case insensitively and returned in the prefix case.
+func matchPrefix(words []string, prefix string, keywords bool) []string {
+	var result []string
+	for _, word := range words {
+		if !keywords {
+			if strings.HasPrefix(word, prefix) {
+				result = append(result, word)
+			}
+			continue
+		}
+
+		if len(word) < len(prefix) || !strings.EqualFold(word[:len(prefix)], prefix) {
+			continue
+		}
+
+		if prefix != "" && unicode.IsLower([]rune(prefix)[0]) {
+			word = strings.ToLower(word)
+		}
+		result = append(result, word)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// unique removes repeated words keeping the first occurrence.
+func unique(words []string) []string {
+	seen := make(map[string]bool, len(words))
+	result := words[:0]
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		result = append(result, word)
+	}
+
+	return result
+}
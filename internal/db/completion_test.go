@@ -0,0 +1,54 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+func TestCompleter_Complete(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte("id,name,age\n1,Bob,30\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "roles.csv"), []byte("id,title\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden.csv"), []byte("id\n"), 0600))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "archive"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "archive", "users2020.csv"), []byte("id\n"), 0600))
+
+	conf := &config.Config{TableLocation: dir, FieldDelimiter: ',', Header: true}
+	completer := NewCompleter(FileTableConnector{}, conf, zap.NewNop())
+
+	keywords := csvquery.Keywords()
+	sort.Strings(keywords)
+
+	tests := []struct {
+		name   string
+		before string
+		word   string
+		after  string
+		want   []string
+	}{
+		{name: "keyword", before: "", word: "se", want: []string{"select"}},
+		{name: "upper case keyword", before: "", word: "W", want: []string{"WHERE", "WITH"}},
+		{name: "tables", before: "select * from ", word: "", want: append([]string{"archive/", "roles.csv", "users.csv"}, keywords...)},
+		{name: "describe", before: "DESC ", word: "r", want: []string{"roles.csv"}},
+		{name: "table prefix", before: "select * from ", word: "u", want: []string{"users.csv"}},
+		{name: "table in directory", before: "select * FROM ", word: "archive/u", want: []string{"archive/users2020.csv"}},
+		{name: "columns after table", before: "select * from users.csv where ", word: "ag", want: []string{"age"}},
+		{name: "columns before table", before: "select ", word: "n", after: " from users.csv, roles.csv", want: []string{"name"}},
+		{name: "columns of several tables", before: "select ", word: "i", after: " from users.csv, roles.csv", want: []string{"id", "into"}},
+		{name: "unknown table", before: "select * from nope.csv where ", word: "i", want: []string{"into"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, completer.Complete(tt.before, tt.word, tt.after))
+		})
+	}
+}
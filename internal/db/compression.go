@@ -0,0 +1,159 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// ErrDecompression indicates that the compressed table can't be read.
+var ErrDecompression = errors.New("can't decompress table")
+
+// magicSize is the size of the file beginning inspected to detect the compression format.
+const magicSize = 10
+
+// compression describes a compression format of tables.
+type compression struct {
+	name       string
+	extensions []string
+	// match returns true if the file beginning has the magic bytes of the format.
+	match func(prefix []byte) bool
+	open  func(io.Reader) (io.Reader, error)
+}
+
+// compressions lists supported compression formats.
+var compressions = []compression{
+	{
+		name:       "gzip",
+		extensions: []string{".gz", ".gzip"},
+		match:      magicPrefix(0x1F, 0x8B, 0x08),
+		open: func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		},
+	},
+	{
+		name:       "zstd",
+		extensions: []string{".zst", ".zstd"},
+		match:      magicPrefix(0x28, 0xB5, 0x2F, 0xFD),
+		open: func(r io.Reader) (io.Reader, error) {
+			decoder, err := zstd.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			return decoder.IOReadCloser(), nil
+		},
+	},
+	{
+		name:       "bzip2",
+		extensions: []string{".bz2", ".bzip2"},
+		match:      isBzip2,
+		open: func(r io.Reader) (io.Reader, error) {
+			return bzip2.NewReader(r), nil
+		},
+	},
+	{
+		name:       "xz",
+		extensions: []string{".xz"},
+		match:      magicPrefix(0xFD, '7', 'z', 'X', 'Z', 0x00),
+		open: func(r io.Reader) (io.Reader, error) {
+			return xz.NewReader(r)
+		},
+	},
+}
+
+// bzip2BlockMagic starts the first block of a bzip2 stream after the "BZh" signature and the level digit.
+var bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+
+func magicPrefix(magic ...byte) func([]byte) bool {
+	return func(prefix []byte) bool {
+		return bytes.HasPrefix(prefix, magic)
+	}
+}
+
+// isBzip2 checks the block magic too because a plain table may start with "BZh".
+func isBzip2(prefix []byte) bool {
+	return len(prefix) >= magicSize && bytes.HasPrefix(prefix, []byte("BZh")) &&
+		prefix[3] >= '1' && prefix[3] <= '9' && bytes.Equal(prefix[4:magicSize], bzip2BlockMagic)
+}
+
+// decompressedFile reads the decompressed table and closes both the decompressor and the file.
+type decompressedFile struct {
+	io.Reader
+	file io.Closer
+	// compressed is false if the file is read as is.
+	compressed bool
+}
+
+func (f *decompressedFile) Close() error {
+	if closer, ok := f.Reader.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
+	return f.file.Close()
+}
+
+// decompress wraps the table file in a decompressor chosen by the file extension or by the magic bytes.
+// Uncompressed files are returned as is.
+func decompress(tablePath string, file io.ReadCloser) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(file)
+
+	format := compressionByExtension(tablePath)
+	if format == nil {
+		format = compressionByMagic(buffered)
+	}
+	if format == nil {
+		return &decompressedFile{Reader: buffered, file: file}, nil
+	}
+
+	reader, err := format.open(buffered)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("%w: %s: %v", ErrDecompression, format.name, err)
+	}
+
+	return &decompressedFile{Reader: reader, file: file, compressed: true}, nil
+}
+
+// compressionByExtension returns the compression format of the file extension or nil.
+func compressionByExtension(tablePath string) *compression {
+	ext := strings.ToLower(path.Ext(tablePath))
+	for i := range compressions {
+		for _, formatExt := range compressions[i].extensions {
+			if ext == formatExt {
+				return &compressions[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// compressionByMagic returns the compression format of the magic bytes at the file beginning or nil.
+func compressionByMagic(reader *bufio.Reader) *compression {
+	prefix, _ := reader.Peek(magicSize)
+	for i := range compressions {
+		if compressions[i].match(prefix) {
+			return &compressions[i]
+		}
+	}
+
+	return nil
+}
+
+// trimCompressionExtension returns the file name without the extension of a compression format.
+func trimCompressionExtension(name string) string {
+	if compressionByExtension(name) == nil {
+		return name
+	}
+
+	return strings.TrimSuffix(name, path.Ext(name))
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
+)
+
+const compressedContent = "id,name\n1,a\n2,b\n"
+
+// bzip2Content is compressedContent compressed by the bzip2 tool, the standard library has no bzip2 writer.
+const bzip2Content = "425a6839314159265359a0307b7e000006d900001000043000362320003100d34d0403102041459746f5edf8bb9229c284850183dbf0"
+
+func compressContent(t *testing.T, format string) []byte {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	var err error
+
+	switch format {
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	case "zstd":
+		writer, err = zstd.NewWriter(&buf)
+	case "xz":
+		writer, err = xz.NewWriter(&buf)
+	case "bzip2":
+		content, err := hex.DecodeString(bzip2Content)
+		assert.NoError(t, err)
+		return content
+	default:
+		return []byte(compressedContent)
+	}
+	assert.NoError(t, err)
+
+	_, err = writer.Write([]byte(compressedContent))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestDecompress(t *testing.T) {
+	tests := []struct {
+		format string
+		path   string
+	}{
+		{format: "gzip", path: "orders.csv.gz"},
+		{format: "gzip", path: "orders.csv"},
+		{format: "zstd", path: "orders.csv.zst"},
+		{format: "zstd", path: "orders"},
+		{format: "bzip2", path: "orders.csv.bz2"},
+		{format: "bzip2", path: "orders.csv"},
+		{format: "xz", path: "orders.csv.xz"},
+		{format: "xz", path: "orders.csv"},
+		{format: "plain", path: "orders.csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.path, func(t *testing.T) {
+			file := &closeRecorder{Reader: bytes.NewReader(compressContent(t, tt.format))}
+			reader, err := decompress(tt.path, file)
+			assert.NoError(t, err)
+
+			content, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, compressedContent, string(content))
+
+			assert.NoError(t, reader.Close())
+			assert.True(t, file.closed)
+		})
+	}
+}
+
+func TestDecompress_error(t *testing.T) {
+	file := &closeRecorder{Reader: bytes.NewReader([]byte(compressedContent))}
+	_, err := decompress("orders.csv.gz", file)
+	assert.ErrorIs(t, err, ErrDecompression)
+	assert.True(t, file.closed)
+}
+
+func TestIsBzip2(t *testing.T) {
+	content, err := hex.DecodeString(bzip2Content)
+	assert.NoError(t, err)
+	assert.True(t, isBzip2(content[:magicSize]))
+	assert.False(t, isBzip2([]byte("BZh1,BZh2,BZh3\n")))
+}
+
+func TestTrimCompressionExtension(t *testing.T) {
+	assert.Equal(t, "orders.csv", trimCompressionExtension("orders.csv.gz"))
+	assert.Equal(t, "orders.csv", trimCompressionExtension("orders.csv.ZST"))
+	assert.Equal(t, "orders.csv", trimCompressionExtension("orders.csv"))
+}
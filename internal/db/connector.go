@@ -1,27 +1,46 @@
 package db
 
 import (
+	"errors"
 	"io"
+	"io/fs"
 	"os"
+	"path"
+	"sync"
 )
 
-// TableConnector is the interface that groups the basic GetReader and Exists methods.
+// Names of the table which is read from stdin.
+const (
+	StdinTable      = "stdin"
+	StdinTableAlias = "-"
+)
+
+var (
+	// ErrStdinUnavailable indicates that stdin can't be read as a table because statements are read from it.
+	ErrStdinUnavailable = errors.New("stdin is used for statements, give them with -e or -f to read stdin as a table")
+	// ErrStdinConsumed indicates that the stdin table is already read by a previous table or statement.
+	ErrStdinConsumed = errors.New("stdin is already read")
+)
+
+// TableConnector is the interface that groups the basic GetReader, Exists, IsDir and List methods.
 type TableConnector interface {
 	GetReader(string) (io.ReadCloser, error)
 	Exists(string) bool
+	IsDir(string) bool
+	List(string) ([]fs.FileInfo, error)
 }
 
 // FileTableConnector implements TableConnector interface for working with files.
 type FileTableConnector struct{}
 
-// GetReader returns file reader.
+// GetReader returns file reader, compressed files are decompressed.
 func (c FileTableConnector) GetReader(tablePath string) (io.ReadCloser, error) {
 	file, err := os.Open(tablePath)
 	if err != nil {
 		return nil, err
 	}
 
-	return file, nil
+	return decompress(tablePath, file)
 }
 
 // Exists checks whether a file exists.
@@ -31,3 +50,90 @@ func (c FileTableConnector) Exists(tablePath string) bool {
 	}
 	return true
 }
+
+// IsDir checks whether the path is a directory.
+func (c FileTableConnector) IsDir(tablePath string) bool {
+	info, err := os.Stat(tablePath)
+	return err == nil && info.IsDir()
+}
+
+// List returns files and directories of the directory sorted by name.
+func (c FileTableConnector) List(dirPath string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			// the file is removed after reading the directory
+			continue
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// StdinTableConnector reads the stdin table from the stdin reader and other tables with the wrapped connector.
+type StdinTableConnector struct {
+	TableConnector
+	location string
+
+	mu sync.Mutex
+	// stdin is nil if it isn't available for reading tables, it's read only once.
+	stdin    io.Reader
+	consumed bool
+}
+
+// NewStdinTableConnector returns the connector reading the stdin table of the table location from stdin,
+// stdin is nil if statements are read from it.
+func NewStdinTableConnector(connector TableConnector, location string, stdin io.Reader) *StdinTableConnector {
+	return &StdinTableConnector{
+		TableConnector: connector,
+		location:       location,
+		stdin:          stdin,
+	}
+}
+
+// GetReader returns stdin for the stdin table, compressed input is decompressed.
+func (c *StdinTableConnector) GetReader(tablePath string) (io.ReadCloser, error) {
+	if !c.isStdin(tablePath) {
+		return c.TableConnector.GetReader(tablePath)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stdin == nil {
+		return nil, ErrStdinUnavailable
+	}
+
+	if c.consumed {
+		return nil, ErrStdinConsumed
+	}
+	c.consumed = true
+
+	return decompress("", io.NopCloser(c.stdin))
+}
+
+// Exists checks whether a file exists, the stdin table always exists.
+func (c *StdinTableConnector) Exists(tablePath string) bool {
+	return c.isStdin(tablePath) || c.TableConnector.Exists(tablePath)
+}
+
+// IsDir checks whether the path is a directory.
+func (c *StdinTableConnector) IsDir(tablePath string) bool {
+	return !c.isStdin(tablePath) && c.TableConnector.IsDir(tablePath)
+}
+
+// isStdin checks whether the table is read from stdin, a real file with the stdin table name is read instead.
+func (c *StdinTableConnector) isStdin(tablePath string) bool {
+	if tablePath != path.Join(c.location, StdinTable) && tablePath != path.Join(c.location, StdinTableAlias) {
+		return false
+	}
+
+	return !c.TableConnector.Exists(tablePath)
+}
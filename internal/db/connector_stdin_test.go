@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+)
+
+func TestStdinTableConnector(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{"users.csv": "file"})
+	connector := NewStdinTableConnector(FileTableConnector{}, dir, strings.NewReader("stdin"))
+
+	assert.True(t, connector.Exists(filepath.Join(dir, "stdin")))
+	assert.True(t, connector.Exists(filepath.Join(dir, "-")))
+	assert.False(t, connector.IsDir(filepath.Join(dir, "stdin")))
+
+	reader, err := connector.GetReader(filepath.Join(dir, "users.csv"))
+	assert.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "file", string(content))
+	assert.NoError(t, reader.Close())
+
+	reader, err = connector.GetReader(filepath.Join(dir, "-"))
+	assert.NoError(t, err)
+	content, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "stdin", string(content))
+	assert.NoError(t, reader.Close())
+
+	_, err = connector.GetReader(filepath.Join(dir, "stdin"))
+	assert.ErrorIs(t, err, ErrStdinConsumed)
+
+	// a real file with the stdin table name isn't shadowed
+	createTables(t, dir, map[string]string{"stdin": "real stdin file"})
+	reader, err = connector.GetReader(filepath.Join(dir, "stdin"))
+	assert.NoError(t, err)
+	content, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "real stdin file", string(content))
+	assert.NoError(t, reader.Close())
+
+	connector = NewStdinTableConnector(FileTableConnector{}, dir, nil)
+	_, err = connector.GetReader(filepath.Join(dir, "-"))
+	assert.ErrorIs(t, err, ErrStdinUnavailable)
+}
+
+func TestExecute_stdinTable(t *testing.T) {
+	dir := t.TempDir()
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+	}
+
+	connector := NewStdinTableConnector(FileTableConnector{}, dir, strings.NewReader("a,b,c\n1,2,3\n4,5,6\n7,8,9\n"))
+	err := Execute(context.Background(), connector, "select a, b from stdin where c > 5 into outfile 'result.csv'", conf, zap.NewNop())
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "result.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b\n4,5\n7,8\n", string(content))
+
+	err = Execute(context.Background(), connector, "select a from - into outfile 'again.csv'", conf, zap.NewNop())
+	assert.ErrorIs(t, err, ErrTableConnection)
+}
@@ -37,6 +37,12 @@ func (s *TableConnectorTestSuite) TestFileTableConnector_Exists() {
 	assert.False(s.T(), s.conn.Exists(s.filename))
 }
 
+func (s *TableConnectorTestSuite) TestFileTableConnector_IsDir() {
+	assert.True(s.T(), s.conn.IsDir("."))
+	assert.False(s.T(), s.conn.IsDir(s.filename))
+	assert.False(s.T(), s.conn.IsDir(s.filename+"_dir"))
+}
+
 func (s *TableConnectorTestSuite) TestFileTableConnector_GetReader() {
 	reader, err := s.conn.GetReader(s.filename)
 	assert.NoError(s.T(), err)
@@ -48,6 +54,20 @@ func (s *TableConnectorTestSuite) TestFileTableConnector_GetReader() {
 	assert.Error(s.T(), err)
 }
 
+func (s *TableConnectorTestSuite) TestFileTableConnector_List() {
+	infos, err := s.conn.List(".")
+	assert.NoError(s.T(), err)
+
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	assert.Contains(s.T(), names, s.filename)
+
+	_, err = s.conn.List(s.filename + "_dir")
+	assert.Error(s.T(), err)
+}
+
 func TestTableConnectorTestSuite(t *testing.T) {
 	suite.Run(t, new(TableConnectorTestSuite))
 }
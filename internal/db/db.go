@@ -7,18 +7,22 @@ import (
 	"os"
 	"strings"
 	"sync"
-	"text/tabwriter"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
 	"github.com/phpCoder88/csv-searcher/internal/config"
 	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+	"github.com/phpCoder88/csv-searcher/internal/output"
 )
 
 var (
 	// ErrQueryTimeout indicates that time for executing query is out.
 	ErrQueryTimeout = errors.New("time for executing query is out")
+	// ErrQueryCanceled indicates that the query is canceled by user.
+	ErrQueryCanceled = errors.New("query canceled")
 	// ErrNotExistColumn indicates that given column doesn't exist.
 	ErrNotExistColumn = errors.New("column doesn't exist")
 	// ErrTableConnection indicates that executor can't connect to the given table.
@@ -35,6 +39,12 @@ var (
 	ErrIncorrectColumnOrder = errors.New("incorrect column order in tables")
 	// ErrIncorrectColumnCount indicates that executor got incorrect column count.
 	ErrIncorrectColumnCount = errors.New("incorrect column count")
+	// ErrTableRead indicates that executor can't read the table.
+	ErrTableRead = errors.New("can't read table")
+	// ErrMalformedRow indicates that a table row can't be parsed in the strict parse mode.
+	ErrMalformedRow = errors.New("malformed table row")
+	// ErrResultWrite indicates that executor can't write the query result.
+	ErrResultWrite = errors.New("can't write query result")
 )
 
 // Execute executes query.
@@ -48,39 +58,110 @@ func Execute(ctx context.Context, connector TableConnector, queryString string,
 	query := csvquery.NewQuery(queryString, logger)
 	db := NewDB(connector, query, logger, conf)
 
-	go db.execute(timeoutCtx)
+	var err error
+	db.catalog, err = loadCatalog(connector, conf.TableLocation)
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+
+	statement, err := csvquery.ParseInfoStatement(queryString)
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+
+	if explain, ok := statement.(*csvquery.ExplainStatement); ok && explain.Analyze {
+		return db.explainAnalyze(ctx, timeoutCtx, explain)
+	}
+
+	if statement != nil {
+		err = db.executeInfo(timeoutCtx, statement)
+		if err != nil && timeoutCtx.Err() != nil {
+			return db.interruptionError(ctx, timeoutCtx)
+		}
+		return err
+	}
+
+	rowCount, err := db.run(ctx, timeoutCtx)
+	if err != nil {
+		return err
+	}
+
+	db.printSummary(rowCount)
+	return nil
+}
+
+// run executes the parsed query writing the result rows to the sink and returns the number of written rows.
+// The query goroutines are canceled and waited for if it fails, so none of them is left blocked on sending.
+func (db *DB) run(ctx, timeoutCtx context.Context) (int, error) {
+	execCtx, cancel := context.WithCancel(timeoutCtx)
+	defer cancel()
+	go db.execute(execCtx)
+
+	var sink resultSink
+	var tableColumns []string
+	var rowCount int
+	finished := false
+	defer func() {
+		if !finished {
+			cancel()
+			db.waitFinished()
+		}
+		if sink != nil {
+			sink.Abort()
+		}
+	}()
 
-	var rows [][]string
 done:
 	for {
 		select {
 		case <-timeoutCtx.Done():
-			t, _ := timeoutCtx.Deadline()
-			if time.Since(t) >= 0 {
-				logger.Error(fmt.Sprintf("Timeout %s", conf.Timeout))
-			}
-			<-db.finishedCh
-			return ErrQueryTimeout
+			return 0, db.interruptionError(ctx, timeoutCtx)
 
 		case <-db.finishedCh:
+			finished = true
 			break done
 
 		case err := <-db.errorCh:
-			return err
+			return 0, err
 
 		case header := <-db.headersCh:
-			if rows == nil {
-				rows = append(rows, header)
+			if sink != nil {
+				err := db.checkTableColumnNames(tableColumns, header)
+				if err != nil {
+					return 0, err
+				}
 				continue
 			}
 
-			err := db.checkTableColumnNames(rows[0], header)
+			var err error
+			sink, err = db.openSink()
 			if err != nil {
-				return err
+				return 0, err
+			}
+
+			tableColumns = header
+			err = sink.WriteHeader(header)
+			if err != nil {
+				return 0, db.sinkError(err)
 			}
 
 		case row := <-db.resultCh:
-			rows = append(rows, row)
+			rowCount++
+			err := sink.WriteRow(row)
+			if err != nil {
+				return 0, db.sinkError(err)
+			}
+		}
+	}
+
+	// all workers are finished, rows left in the buffer are still to be written
+	for len(db.resultCh) > 0 {
+		rowCount++
+		err := sink.WriteRow(<-db.resultCh)
+		if err != nil {
+			return 0, db.sinkError(err)
 		}
 	}
 
@@ -90,7 +171,21 @@ done:
 	close(db.headersCh)
 	close(db.finishedCh)
 
-	return db.printResult(rows)
+	if sink == nil {
+		var err error
+		sink, err = db.openSink()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err := sink.Close()
+	sink = nil
+	if err != nil {
+		return 0, db.sinkError(err)
+	}
+
+	return rowCount, nil
 }
 
 // DB describes file database.
@@ -107,6 +202,13 @@ type DB struct {
 	headersCh  chan []string
 	start      time.Time
 	execTime   time.Duration
+
+	// analyze is true if execution statistics are collected, the result rows are discarded then.
+	analyze bool
+	tables  []*Table
+
+	// catalog contains per-table format options, it's nil if the table location has no catalog file.
+	catalog *catalog.Catalog
 }
 
 // NewDB returns new instance of DB.
@@ -118,7 +220,7 @@ func NewDB(connector TableConnector, query *csvquery.Query, logger *zap.Logger,
 		errorCh:    make(chan error),
 		logger:     logger,
 		config:     conf,
-		resultCh:   make(chan []string, conf.Limit),
+		resultCh:   make(chan []string, conf.Workers),
 		headersCh:  make(chan []string),
 		start:      time.Now(),
 	}
@@ -140,16 +242,21 @@ func (db *DB) execute(ctx context.Context) {
 
 func (db *DB) executeQuery(ctx context.Context) {
 	var wg sync.WaitGroup
+	var prevDone <-chan struct{}
 
-	for _, tableName := range db.query.From {
-		table := NewTable(tableName, db.query, db)
-		if !table.Exists() {
-			err := fmt.Errorf("%w: table '%s' doen't exist", csvquery.ErrIncorrectQuery, tableName)
-			db.logger.Error(err.Error())
-			db.errorCh <- err
+	tables, err := db.resolveTables(ctx)
+	if err != nil {
+		db.logger.Error(err.Error())
+		db.errorCh <- err
 
-			return
-		}
+		return
+	}
+
+	for _, tableName := range tables {
+		table := NewTable(tableName, db.query, db)
+		table.prev = prevDone
+		prevDone = table.done
+		db.tables = append(db.tables, table)
 
 		wg.Add(1)
 		go func() {
@@ -181,42 +288,79 @@ func (db *DB) checkTableColumnNames(currentHeaders, headers []string) error {
 	return nil
 }
 
-func (db *DB) printResult(rows [][]string) error {
-	rowCount := len(rows) - 1
-	if rowCount == 0 {
-		fmt.Printf("Empty set (%.3f sec)\n\n", db.execTime.Seconds())
-		return nil
+// waitFinished waits for the canceled query goroutines, values they are still sending are discarded.
+func (db *DB) waitFinished() {
+	for {
+		select {
+		case <-db.finishedCh:
+			return
+		case <-db.headersCh:
+		case <-db.resultCh:
+		case <-db.errorCh:
+		}
 	}
+}
 
-	var err error
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.AlignRight|tabwriter.Debug)
+// interruptionError returns the error of the query interrupted by the user or by the timeout.
+func (db *DB) interruptionError(ctx, timeoutCtx context.Context) error {
+	if ctx.Err() != nil {
+		db.logger.Info("Query canceled")
+		return ErrQueryCanceled
+	}
 
-loop:
-	for _, row := range rows {
-		for _, item := range row {
-			_, err = fmt.Fprintf(w, "%s\t", item)
-			if err != nil {
-				break loop
-			}
-		}
-		_, err = fmt.Fprintf(w, "\n")
-		if err != nil {
-			break loop
+	t, _ := timeoutCtx.Deadline()
+	if time.Since(t) >= 0 {
+		db.logger.Error(fmt.Sprintf("Timeout %s", db.config.Timeout))
+	}
+	return ErrQueryTimeout
+}
+
+// printSummary prints the query summary after the result table.
+// Warnings about skipped malformed rows are printed to stderr in other formats not to mix them with the result.
+func (db *DB) printSummary(rowCount int) {
+	warnings := db.rejectedWarnings()
+	if output.Format(db.config.Format) != output.FormatTable {
+		for _, warning := range warnings {
+			_, _ = fmt.Fprintln(os.Stderr, warning)
 		}
+		return
 	}
 
-	if err != nil {
-		db.logger.Error(err.Error())
-		return err
+	switch {
+	case db.query.Into != nil:
+		fmt.Printf("Query OK, %d rows written to '%s' (%.3f sec)\n", rowCount, db.query.Into.Path, db.execTime.Seconds())
+	case rowCount == 0:
+		fmt.Printf("Empty set (%.3f sec)\n", db.execTime.Seconds())
+	default:
+		fmt.Printf("%s\n", strings.Repeat("-", 30))
+		fmt.Printf("%d rows in set (%.3f sec)\n", rowCount, db.execTime.Seconds())
 	}
 
-	err = w.Flush()
-	if err != nil {
-		db.logger.Error(err.Error())
-		return err
+	for _, warning := range warnings {
+		fmt.Println(warning)
 	}
+	fmt.Println()
+}
 
-	fmt.Printf("%s\n", strings.Repeat("-", 30))
-	fmt.Printf("%d rows in set (%.3f sec)\n\n", rowCount, db.execTime.Seconds())
-	return nil
+// limit returns the limit of selected rows, zero means no limit.
+// The session limit caps only rows shown on stdout, exports to a file get all rows.
+func (db *DB) limit() int32 {
+	if db.query != nil && db.query.Into != nil {
+		return 0
+	}
+
+	return db.config.Limit
+}
+
+// reserveRow reserves a place for one more selected row and returns false if the limit is reached.
+func (db *DB) reserveRow() bool {
+	selected := atomic.AddInt32(&db.selected, 1)
+	limit := db.limit()
+	return limit <= 0 || selected <= limit
+}
+
+// limitReached returns true if the limit of selected rows is reached, zero limit means no limit.
+func (db *DB) limitReached() bool {
+	limit := db.limit()
+	return limit > 0 && atomic.LoadInt32(&db.selected) >= limit
 }
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+)
+
+// ErrUnknownEncoding indicates that the table encoding isn't supported.
+var ErrUnknownEncoding = errors.New("unknown encoding")
+
+var (
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+var (
+	utf16LE = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	utf16BE = unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+)
+
+// tableEncoding returns the encoding of the byte order mark, the one set in the catalog or the session
+// or the one detected by the table beginning.
+func (t *Table) tableEncoding(sample []byte, full bool) (encoding.Encoding, error) {
+	if enc := bomEncoding(sample); enc != nil {
+		return enc, nil
+	}
+
+	name := t.options.Encoding
+	if name == "" {
+		name = t.db.config.Encoding
+	}
+
+	if name == "" || strings.EqualFold(name, config.EncodingAuto) {
+		return detectEncoding(sample, full), nil
+	}
+
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownEncoding, name)
+	}
+
+	return enc, nil
+}
+
+// bomEncoding returns the encoding of the byte order mark at the sample beginning or nil.
+// Decoders keep the mark, it's skipped as a UTF-8 one after decoding.
+func bomEncoding(sample []byte) encoding.Encoding {
+	switch {
+	case bytes.HasPrefix(sample, utf8BOM):
+		return unicode.UTF8
+	case bytes.HasPrefix(sample, utf16LEBOM):
+		return utf16LE
+	case bytes.HasPrefix(sample, utf16BEBOM):
+		return utf16BE
+	}
+
+	return nil
+}
+
+// detectEncoding returns UTF-16 if every second byte is mostly zero, UTF-8 for valid UTF-8 text,
+// Windows-1251 if non-ASCII bytes mostly form words and Windows-1252 otherwise.
+func detectEncoding(sample []byte, full bool) encoding.Encoding {
+	var evenZeros, oddZeros int
+	for i, b := range sample {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+
+	switch {
+	case oddZeros > len(sample)/4 && oddZeros > evenZeros:
+		return utf16LE
+	case evenZeros > len(sample)/4 && evenZeros > oddZeros:
+		return utf16BE
+	case isUTF8(sample, full):
+		return unicode.UTF8
+	}
+
+	// Cyrillic words consist of non-ASCII bytes only, accented Latin letters are mostly single.
+	var high, adjacent int
+	for i, b := range sample {
+		if b < utf8.RuneSelf {
+			continue
+		}
+
+		high++
+		if (i > 0 && sample[i-1] >= utf8.RuneSelf) || (i+1 < len(sample) && sample[i+1] >= utf8.RuneSelf) {
+			adjacent++
+		}
+	}
+
+	if adjacent*2 > high {
+		return charmap.Windows1251
+	}
+
+	return charmap.Windows1252
+}
+
+// isUTF8 returns true if the sample is valid UTF-8, a rune cut at the end of the not full sample is allowed.
+func isUTF8(sample []byte, full bool) bool {
+	if utf8.Valid(sample) {
+		return true
+	}
+
+	if full {
+		return false
+	}
+
+	for cut := 1; cut < utf8.UTFMax && cut <= len(sample); cut++ {
+		tail := sample[len(sample)-cut:]
+		if utf8.RuneStart(tail[0]) && !utf8.FullRune(tail) && utf8.Valid(sample[:len(sample)-cut]) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+func encode(t *testing.T, enc encoding.Encoding, content string) string {
+	encoded, err := enc.NewEncoder().String(content)
+	assert.NoError(t, err)
+	return encoded
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample string
+		full   bool
+		want   encoding.Encoding
+	}{
+		{
+			name:   "ascii",
+			sample: "id,name\n1,Bob\n",
+			full:   true,
+			want:   unicode.UTF8,
+		},
+		{
+			name:   "utf-8 cut in the middle of a rune",
+			sample: "id,name\n1,Иван"[:len("id,name\n1,Иван")-1],
+			want:   unicode.UTF8,
+		},
+		{
+			name:   "utf-16le without bom",
+			sample: encode(t, utf16LE, "id,name\n1,Bob\n"),
+			full:   true,
+			want:   utf16LE,
+		},
+		{
+			name:   "utf-16be without bom",
+			sample: encode(t, utf16BE, "id,name\n1,Bob\n"),
+			full:   true,
+			want:   utf16BE,
+		},
+		{
+			name:   "windows-1251",
+			sample: encode(t, charmap.Windows1251, "id,name\n1,Иван Петров\n"),
+			full:   true,
+			want:   charmap.Windows1251,
+		},
+		{
+			name:   "windows-1252",
+			sample: encode(t, charmap.Windows1252, "id,name\n1,José Müller\n"),
+			full:   true,
+			want:   charmap.Windows1252,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectEncoding([]byte(tt.sample), tt.full))
+		})
+	}
+}
+
+func TestTable_connectEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		encoding string
+	}{
+		{
+			name:    "utf-8 bom",
+			content: "\xEF\xBB\xBFid;name\n1;Иван\n",
+		},
+		{
+			name:    "utf-16le bom",
+			content: "\xFF\xFE" + encode(t, utf16LE, "id;name\n1;Иван\n"),
+		},
+		{
+			name:    "utf-16be bom",
+			content: "\xFE\xFF" + encode(t, utf16BE, "id;name\n1;Иван\n"),
+		},
+		{
+			name:    "detected windows-1251",
+			content: encode(t, charmap.Windows1251, "id;name\n1;Иван\n"),
+		},
+		{
+			name:     "declared koi8-r",
+			content:  encode(t, charmap.KOI8R, "id;name\n1;Иван\n"),
+			encoding: "koi8-r",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{FieldDelimiter: ',', Header: true, Sniff: true, Encoding: config.EncodingAuto}
+			db := NewDB(stringConnector{content: tt.content}, &csvquery.Query{}, zap.NewNop(), conf)
+			db.catalog = &catalog.Catalog{Tables: map[string]catalog.TableOptions{
+				"users.csv": {Encoding: tt.encoding},
+			}}
+
+			table := NewTable("users.csv", db.query, db)
+			source, err := table.connect()
+			assert.NoError(t, err)
+
+			header, err := source.readHeader()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"id", "name"}, header)
+
+			row, err := source.read()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"1", "Иван"}, row)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+	"github.com/phpCoder88/csv-searcher/internal/output"
+)
+
+// planIndent indents nested lines of the query plan.
+const planIndent = "  "
+
+// explain parses the explained query and prints its plan without reading tables.
+func (db *DB) explain(stmt *csvquery.ExplainStatement) error {
+	db.query = csvquery.NewQuery(stmt.Query, db.logger)
+	err := db.query.Parse()
+	if err != nil {
+		return err
+	}
+
+	plan, err := db.plan()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(os.Stdout, "%s\n\n", strings.Join(plan, "\n"))
+	if err != nil {
+		return db.sinkError(err)
+	}
+
+	return nil
+}
+
+// explainAnalyze executes the explained query discarding the result rows
+// and prints the query plan with the execution statistics of every table.
+func (db *DB) explainAnalyze(ctx, timeoutCtx context.Context, stmt *csvquery.ExplainStatement) error {
+	db.query = csvquery.NewQuery(stmt.Query, db.logger)
+	db.analyze = true
+
+	rowCount, err := db.run(ctx, timeoutCtx)
+	if err != nil {
+		return err
+	}
+
+	plan, err := db.plan()
+	if err != nil {
+		return err
+	}
+
+	plan = append(plan,
+		"Execution:",
+		fmt.Sprintf("%sRows returned: %d", planIndent, rowCount),
+		fmt.Sprintf("%sTime: %s", planIndent, db.execTime.Round(time.Microsecond)),
+	)
+	for _, table := range db.tables {
+		plan = append(plan, fmt.Sprintf("Table %s:", table.name))
+		plan = append(plan, table.stats.lines(planIndent)...)
+	}
+
+	_, err = fmt.Fprintf(os.Stdout, "%s\n\n", strings.Join(plan, "\n"))
+	if err != nil {
+		return db.sinkError(err)
+	}
+
+	return nil
+}
+
+// plan returns lines describing how the parsed query is executed.
+func (db *DB) plan() ([]string, error) {
+	query := db.query
+	lines := []string{"Query: " + query.Statement().String(), "Tables:"}
+
+	for _, name := range query.From {
+		file, _ := splitSheet(string(name))
+		tablePath := path.Join(db.config.TableLocation, file)
+		if isGlob(file) || db.connector.IsDir(tablePath) {
+			files, err := db.resolveTable(context.Background(), name)
+			if err != nil {
+				return nil, err
+			}
+
+			lines = append(lines, fmt.Sprintf("%s%s -> %s (%d files)", planIndent, name, tablePath, len(files)))
+			for _, file := range files {
+				lines = append(lines, planIndent+planIndent+string(file))
+			}
+			continue
+		}
+
+		state := "exists"
+		if !db.connector.Exists(tablePath) {
+			state = "not found"
+		}
+		lines = append(lines, fmt.Sprintf("%s%s -> %s (%s)", planIndent, name, tablePath, state))
+	}
+
+	lines = append(lines,
+		"Projected columns: "+joinColumns(query.Select),
+		"Used columns: "+joinColumns(csvquery.Columns(query.UsedColumns)),
+	)
+
+	if query.Where == nil {
+		lines = append(lines, "Where: none")
+	} else {
+		lines = append(lines, "Where:")
+		lines = append(lines, exprLines(query.Where, 1)...)
+	}
+
+	limit := "unlimited"
+	if db.limit() > 0 {
+		limit = fmt.Sprint(db.limit())
+	}
+
+	outputMode, err := db.outputMode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(lines,
+		"Limit: "+limit,
+		fmt.Sprintf("Workers: %d per table", db.config.Workers),
+		"Output: "+outputMode,
+	), nil
+}
+
+// outputMode describes where and how the query result is written.
+func (db *DB) outputMode() (string, error) {
+	order := "ordered"
+	if !db.config.Ordered {
+		order = "unordered"
+	}
+
+	into := db.query.Into
+	if into == nil {
+		mode := fmt.Sprintf("stdout, %s format, %s", db.config.Format, order)
+		if output.Format(db.config.Format) == output.FormatTable && db.config.PreviewRows > 0 {
+			mode += fmt.Sprintf(", column widths from first %d rows", db.config.PreviewRows)
+		}
+		return mode, nil
+	}
+
+	filePath, err := db.outFilePath(into.Path)
+	if err != nil {
+		return "", err
+	}
+
+	delimiter := into.Delimiter
+	if delimiter == 0 {
+		delimiter = db.config.FieldDelimiter
+	}
+
+	return fmt.Sprintf("file %s, delimiter %q, header %t, quote %s, %s",
+		filePath, delimiter, into.Header, into.Quote, order), nil
+}
+
+// exprLines returns the where expression tree, one node per line.
+func exprLines(node csvquery.Expr, depth int) []string {
+	indent := strings.Repeat(planIndent, depth)
+
+	expr, ok := node.(*csvquery.BinaryExpr)
+	if !ok {
+		return []string{indent + node.String()}
+	}
+
+	lines := []string{indent + string(expr.Op)}
+	lines = append(lines, exprLines(expr.Left, depth+1)...)
+	return append(lines, exprLines(expr.Right, depth+1)...)
+}
+
+func joinColumns(columns csvquery.Columns) string {
+	if len(columns) == 0 {
+		return "none"
+	}
+
+	names := make([]string, 0, len(columns))
+	for _, col := range columns {
+		names = append(names, col.String())
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+func TestDB_plan(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte("id,name\n"), 0600))
+
+	conf := &config.Config{TableLocation: dir, Workers: 4, Format: "json", FieldDelimiter: ','}
+	db := NewDB(FileTableConnector{}, nil, zap.NewNop(), conf)
+
+	db.query = csvquery.NewQuery("select name from users.csv, roles.csv where age > 30 or name = 'Bob' and age < 50", zap.NewNop())
+	assert.NoError(t, db.query.Parse())
+	db.query.UsedColumns = csvquery.QueryColumns{"name", "age"}
+
+	plan, err := db.plan()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Query: SELECT name FROM users.csv, roles.csv WHERE age > 30 OR name = 'Bob' AND age < 50",
+		"Tables:",
+		"  users.csv -> " + filepath.Join(dir, "users.csv") + " (exists)",
+		"  roles.csv -> " + filepath.Join(dir, "roles.csv") + " (not found)",
+		"Projected columns: name",
+		"Used columns: name, age",
+		"Where:",
+		"  OR",
+		"    age > 30",
+		"    AND",
+		"      name = 'Bob'",
+		"      age < 50",
+		"Limit: unlimited",
+		"Workers: 4 per table",
+		"Output: stdout, json format, unordered",
+	}, plan)
+
+	conf.Limit = 10
+	conf.Ordered = true
+	db.query = csvquery.NewQuery("select * from users.csv into outfile 'result.csv' with (delimiter '\\t')", zap.NewNop())
+	assert.NoError(t, db.query.Parse())
+
+	plan, err = db.plan()
+	assert.NoError(t, err)
+	// the session limit doesn't apply to exports
+	assert.Equal(t, []string{
+		"Where: none",
+		"Limit: unlimited",
+		"Workers: 4 per table",
+		"Output: file " + filepath.Join(dir, "result.csv") + ", delimiter '\\t', header true, quote minimal, ordered",
+	}, plan[len(plan)-4:])
+}
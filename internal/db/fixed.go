@@ -0,0 +1,155 @@
+package db
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+)
+
+// ErrIncorrectLayout indicates that the column layout of a fixed-width table is missing or malformed.
+var ErrIncorrectLayout = errors.New("incorrect fixed-width layout")
+
+// layoutExtension is the extension of the sidecar layout file, orders.dat is described by orders.dat.layout.
+const layoutExtension = ".layout"
+
+// fixedSource reads rows of a fixed-width table, values are cut by the layout and trimmed of spaces.
+// Values beyond the end of a short line are empty.
+type fixedSource struct {
+	table  *Table
+	lines  lineReader
+	layout []catalog.FixedColumn
+}
+
+func (s *fixedSource) readHeader() ([]string, error) {
+	if s.table.hasHeader() {
+		_, err := s.lines.nextRecord(s.table.options.CommentRune())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	columns := make([]string, len(s.layout))
+	for i, column := range s.layout {
+		columns[i] = column.Name
+	}
+
+	return columns, nil
+}
+
+func (s *fixedSource) read() ([]string, error) {
+	line, err := s.lines.nextRecord(s.table.options.CommentRune())
+	if err != nil {
+		return nil, err
+	}
+
+	chars := []rune(line)
+	row := make([]string, len(s.layout))
+	for i, column := range s.layout {
+		start := column.Start - 1
+		if start >= len(chars) {
+			continue
+		}
+
+		end := start + column.Length
+		if end > len(chars) {
+			end = len(chars)
+		}
+		row[i] = strings.TrimSpace(string(chars[start:end]))
+	}
+
+	return row, nil
+}
+
+// layoutPath returns the path of the sidecar layout file of the table, compression extensions are skipped.
+func layoutPath(tablePath string) string {
+	return trimCompressionExtension(tablePath) + layoutExtension
+}
+
+// isLayoutFile returns true if the file is a sidecar layout file.
+func isLayoutFile(name string) bool {
+	return strings.ToLower(path.Ext(name)) == layoutExtension
+}
+
+// resolveLayout reads the sidecar layout of a table which layout isn't given in the catalog.
+// A table with a sidecar layout is fixed-width unless the catalog or the file extension defines another format.
+func (t *Table) resolveLayout() error {
+	if len(t.options.Layout) > 0 || t.format != catalog.FormatFixedWidth && (t.options.Format != "" || isTableFile(string(t.name))) {
+		return nil
+	}
+
+	sidecar := layoutPath(t.path())
+	if !t.db.connector.Exists(sidecar) {
+		if t.format == catalog.FormatFixedWidth {
+			return fmt.Errorf("%w: neither the catalog nor %s describes columns", ErrIncorrectLayout, path.Base(sidecar))
+		}
+		return nil
+	}
+
+	file, err := t.db.connector.GetReader(sidecar)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	layout, err := parseLayout(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path.Base(sidecar), err)
+	}
+
+	t.options.Layout = layout
+	t.format = catalog.FormatFixedWidth
+	t.header = t.options.HasHeader(false)
+
+	return nil
+}
+
+// parseLayout reads a layout spec, every line is a column name, its 1-based start and its length
+// separated by spaces. Empty lines and lines starting with # are skipped.
+func parseLayout(reader io.Reader) ([]catalog.FixedColumn, error) {
+	var layout []catalog.FixedColumn
+
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%w: line %d: name, start and length expected", ErrIncorrectLayout, line)
+		}
+
+		start, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: start: %v", ErrIncorrectLayout, line, err)
+		}
+
+		length, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: length: %v", ErrIncorrectLayout, line, err)
+		}
+
+		layout = append(layout, catalog.FixedColumn{Name: fields[0], Start: start, Length: length})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(layout) == 0 {
+		return nil, fmt.Errorf("%w: no columns", ErrIncorrectLayout)
+	}
+
+	if err := catalog.ValidateLayout(layout); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIncorrectLayout, err)
+	}
+
+	return layout, nil
+}
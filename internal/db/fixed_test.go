@@ -0,0 +1,108 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/config"
+)
+
+func TestParseLayout(t *testing.T) {
+	layout, err := parseLayout(strings.NewReader("# accounts\nid 1 6\n\nname   7 20\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []catalog.FixedColumn{{Name: "id", Start: 1, Length: 6}, {Name: "name", Start: 7, Length: 20}}, layout)
+
+	for _, spec := range []string{"", "# empty\n", "id 1\n", "id one 6\n", "id 1 six\n", "id 0 6\n", "id 1 -1\n"} {
+		_, err = parseLayout(strings.NewReader(spec))
+		assert.ErrorIs(t, err, ErrIncorrectLayout, spec)
+	}
+}
+
+func TestFixedSource(t *testing.T) {
+	table := &Table{header: true}
+	source := &fixedSource{
+		table: table,
+		lines: lineReader{reader: bufio.NewReader(strings.NewReader("ID    NAME      \r\n000001Иван      42\n\n000002Bob\n00\n"))},
+		layout: []catalog.FixedColumn{
+			{Name: "id", Start: 1, Length: 6},
+			{Name: "name", Start: 7, Length: 10},
+			{Name: "age", Start: 17, Length: 3},
+		},
+	}
+
+	header, err := source.readHeader()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "name", "age"}, header)
+
+	var rows [][]string
+	for {
+		row, err := source.read()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		rows = append(rows, row)
+	}
+
+	assert.Equal(t, [][]string{{"000001", "Иван", "42"}, {"000002", "Bob", ""}, {"00", "", ""}}, rows)
+}
+
+func TestExecute_fixedWidth(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{
+		"accounts.dat":        "000001Alice     0001500\n000002Bob       0000020\n",
+		"accounts.dat.layout": "# account export\nid      1  6\nname    7 10\nbalance 17 7\n",
+		"cards.txt":           "CARD    OWNER\n4111    000002\n5500    000001\n",
+		catalog.FileName: `tables:
+  cards.txt:
+    header: true
+    layout:
+      - {name: card, start: 1, length: 8}
+      - {name: owner, start: 9, length: 6}
+`,
+	})
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+	}
+
+	err := Execute(context.Background(), FileTableConnector{}, "select name, $3 from accounts.dat where balance > 100 into outfile 'rich.csv'", conf, zap.NewNop())
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "rich.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "name,balance\nAlice,0001500\n", string(content))
+
+	err = Execute(context.Background(), FileTableConnector{}, "select card from cards.txt where owner = '000001' into outfile 'cards.csv'", conf, zap.NewNop())
+	assert.NoError(t, err)
+
+	content, err = os.ReadFile(filepath.Join(dir, "cards.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "card\n5500\n", string(content))
+
+	conf.TableLocation = t.TempDir()
+	createTables(t, conf.TableLocation, map[string]string{
+		"plain.dat":      "000001Alice\n",
+		catalog.FileName: "tables:\n  plain.dat:\n    format: fixed\n",
+	})
+	err = Execute(context.Background(), FileTableConnector{}, "select id from plain.dat", conf, zap.NewNop())
+	if assert.ErrorIs(t, err, ErrTableConnection) {
+		assert.Contains(t, err.Error(), "plain.dat.layout")
+	}
+}
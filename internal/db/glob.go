@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+// fileColumn is the virtual column with the name of the table file the row is read from.
+const fileColumn csvquery.Column = "_file"
+
+// globMeta contains characters which make a table name a glob pattern.
+const globMeta = "*?["
+
+// isGlob returns true if the table name is a glob pattern.
+func isGlob(name string) bool {
+	return strings.ContainsAny(name, globMeta)
+}
+
+// resolveTables returns the table files of FROM, glob patterns and directories are replaced with the files they match.
+func (db *DB) resolveTables(ctx context.Context) (csvquery.Tables, error) {
+	var tables csvquery.Tables
+
+	for _, name := range db.query.From {
+		files, err := db.resolveTable(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) == 0 {
+			return nil, fmt.Errorf("%w: table '%s' doesn't exist", csvquery.ErrIncorrectQuery, name)
+		}
+
+		tables = append(tables, files...)
+	}
+
+	return tables, nil
+}
+
+// resolveTable returns the files of the table name, the glob pattern or the directory sorted by name.
+// The sheet of a workbook glob pattern like 'reports/*.xlsx'!Totals is kept in the names of the matching files.
+func (db *DB) resolveTable(ctx context.Context, name csvquery.Table) (csvquery.Tables, error) {
+	file, sheet := splitSheet(string(name))
+	tablePath := path.Join(db.config.TableLocation, file)
+
+	switch {
+	case isGlob(file):
+		tables, err := db.globTables(file)
+		return withSheet(tables, sheet), err
+	case db.connector.IsDir(tablePath):
+		var tables csvquery.Tables
+		err := db.walkTables(ctx, path.Clean(string(name)), func(file string, _ fs.FileInfo) {
+			tables = append(tables, csvquery.Table(file))
+		})
+		return tables, err
+	case db.connector.Exists(tablePath):
+		return csvquery.Tables{name}, nil
+	}
+
+	return nil, nil
+}
+
+// globTables returns files of the table location matching the pattern, every path segment may be a pattern.
+// Hidden files and directories match only patterns starting with a dot, the catalog and layout files are never matched.
+func (db *DB) globTables(pattern string) (csvquery.Tables, error) {
+	segments := strings.Split(path.Clean(pattern), "/")
+	dirs := []string{""}
+	var tables csvquery.Tables
+
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		var next []string
+
+		for _, dir := range dirs {
+			if !isGlob(segment) {
+				name := path.Join(dir, segment)
+				tablePath := path.Join(db.config.TableLocation, name)
+				switch {
+				case !last && db.connector.IsDir(tablePath):
+					next = append(next, name)
+				case last && db.connector.Exists(tablePath) && !db.connector.IsDir(tablePath):
+					tables = append(tables, csvquery.Table(name))
+				}
+				continue
+			}
+
+			infos, err := db.connector.List(path.Join(db.config.TableLocation, dir))
+			if err != nil {
+				continue
+			}
+
+			for _, info := range infos {
+				if info.Name() == catalog.FileName || isLayoutFile(info.Name()) || strings.HasPrefix(info.Name(), ".") && !strings.HasPrefix(segment, ".") {
+					continue
+				}
+
+				matched, err := path.Match(segment, info.Name())
+				if err != nil {
+					return nil, fmt.Errorf("%w: table pattern '%s': %v", csvquery.ErrIncorrectQuery, pattern, err)
+				}
+
+				switch {
+				case !matched:
+				case !last && info.IsDir():
+					next = append(next, path.Join(dir, info.Name()))
+				case last && !info.IsDir():
+					tables = append(tables, csvquery.Table(path.Join(dir, info.Name())))
+				}
+			}
+		}
+
+		dirs = next
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+func createTables(t *testing.T, dir string, tables map[string]string) {
+	for name, content := range tables {
+		tablePath := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(tablePath), 0700))
+		assert.NoError(t, os.WriteFile(tablePath, []byte(content), 0600))
+	}
+}
+
+func TestDB_resolveTables(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{
+		"users.csv":                "id\n",
+		"logs/2021-01-02.csv":      "id\n",
+		"logs/2021-01-01.csv":      "id\n",
+		"logs/2020-12-31.csv":      "id\n",
+		"logs/.2021-01-03.csv":     "id\n",
+		"logs/notes.txt":           "notes\n",
+		"logs/archive/2019.csv":    "id\n",
+		"logs/archive/2021-06.csv": "id\n",
+	})
+
+	tests := []struct {
+		from    string
+		want    csvquery.Tables
+		wantErr error
+	}{
+		{
+			from: "users.csv",
+			want: csvquery.Tables{"users.csv"},
+		},
+		{
+			from: "'logs/2021-*.csv'",
+			want: csvquery.Tables{"logs/2021-01-01.csv", "logs/2021-01-02.csv"},
+		},
+		{
+			from: "logs/*/2021-*.csv, users.csv",
+			want: csvquery.Tables{"logs/archive/2021-06.csv", "users.csv"},
+		},
+		{
+			from: "logs/",
+			want: csvquery.Tables{"logs/2020-12-31.csv", "logs/2021-01-01.csv", "logs/2021-01-02.csv", "logs/archive/2019.csv", "logs/archive/2021-06.csv"},
+		},
+		{
+			from:    "logs/2022-*.csv",
+			wantErr: csvquery.ErrIncorrectQuery,
+		},
+		{
+			from:    "roles.csv",
+			wantErr: csvquery.ErrIncorrectQuery,
+		},
+		{
+			from:    "'logs/[.csv'",
+			wantErr: csvquery.ErrIncorrectQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from, func(t *testing.T) {
+			query := csvquery.NewQuery("select id from "+tt.from, zap.NewNop())
+			assert.NoError(t, query.Parse())
+
+			db := NewDB(FileTableConnector{}, query, zap.NewNop(), &config.Config{TableLocation: dir})
+			tables, err := db.resolveTables(context.Background())
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, tables)
+		})
+	}
+}
+
+func TestExecute_globTables(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{
+		"logs/2021-01-01.csv":  "level,message\ninfo,started\nerror,failed\n",
+		"logs/2021-01-02.csv":  "level,message\nerror,timeout\n",
+		"other/2021-01-01.csv": "message,level\nstarted,info\n",
+	})
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+	}
+
+	tests := []struct {
+		query   string
+		file    string
+		want    string
+		wantErr error
+	}{
+		{
+			query: "select _file, message from 'logs/2021-*.csv' where level = 'error' into outfile 'errors.csv'",
+			file:  "errors.csv",
+			want:  "_file,message\nlogs/2021-01-01.csv,failed\nlogs/2021-01-02.csv,timeout\n",
+		},
+		{
+			query: "select *, _file from logs where _file > 'logs/2021-01-01.csv' into outfile 'all.csv'",
+			file:  "all.csv",
+			want:  "level,message,_file\nerror,timeout,logs/2021-01-02.csv\n",
+		},
+		{
+			query:   "select * from logs, other into outfile 'mixed.csv'",
+			wantErr: ErrIncorrectColumnOrder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			err := Execute(context.Background(), FileTableConnector{}, tt.query, conf, zap.NewNop())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			content, err := os.ReadFile(filepath.Join(dir, tt.file))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(content))
+		})
+	}
+}
+
+func TestDB_planGlob(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{
+		"logs/2021-01-01.csv": "id\n",
+		"logs/2021-01-02.csv": "id\n",
+	})
+
+	db := NewDB(FileTableConnector{}, nil, zap.NewNop(), &config.Config{TableLocation: dir, Format: "csv"})
+	db.query = csvquery.NewQuery("select id from 'logs/*.csv'", zap.NewNop())
+	assert.NoError(t, db.query.Parse())
+
+	plan, err := db.plan()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Tables:",
+		"  logs/*.csv -> " + filepath.Join(dir, "logs/*.csv") + " (2 files)",
+		"    logs/2021-01-01.csv",
+		"    logs/2021-01-02.csv",
+	}, plan[1:5])
+}
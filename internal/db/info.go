@@ -0,0 +1,285 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+const (
+	// describeSampleRows is the number of rows read to describe table columns.
+	describeSampleRows = 1000
+	// modTimeLayout is the layout of the table modification time.
+	modTimeLayout = "2006-01-02 15:04:05"
+)
+
+// Column types inferred from the sampled values.
+const (
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeString  = "string"
+	typeUnknown = "unknown"
+)
+
+// executeInfo executes the statement describing tables and writes the result as a query result.
+func (db *DB) executeInfo(ctx context.Context, statement csvquery.Statement) error {
+	var header []string
+	var rows [][]string
+	var err error
+
+	switch stmt := statement.(type) {
+	case *csvquery.ShowTablesStatement:
+		header = []string{"table", "size", "modified"}
+		rows, err = db.showTables(ctx)
+	case *csvquery.DescribeStatement:
+		header = []string{"column", "type", "null_ratio", "example"}
+		rows, err = db.describeTable(ctx, stmt.Table)
+	case *csvquery.ExplainStatement:
+		return db.explain(stmt)
+	default:
+		err = fmt.Errorf("%w: unsupported statement %s", csvquery.ErrIncorrectQuery, statement)
+	}
+
+	if err != nil {
+		db.logger.Error(err.Error())
+		return err
+	}
+
+	sink, err := db.openSink()
+	if err != nil {
+		return err
+	}
+
+	err = writeRows(sink, header, rows)
+	if err != nil {
+		sink.Abort()
+		return db.sinkError(err)
+	}
+
+	err = sink.Close()
+	if err != nil {
+		return db.sinkError(err)
+	}
+
+	db.execTime = time.Since(db.start)
+	db.printSummary(len(rows))
+	return nil
+}
+
+func writeRows(sink resultSink, header []string, rows [][]string) error {
+	err := sink.WriteHeader(header)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err = sink.WriteRow(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// showTables lists csv files of the table location and its subdirectories.
+func (db *DB) showTables(ctx context.Context) ([][]string, error) {
+	var rows [][]string
+	err := db.walkTables(ctx, "", func(name string, info fs.FileInfo) {
+		rows = append(rows, []string{
+			name,
+			strconv.FormatInt(info.Size(), 10),
+			info.ModTime().Format(modTimeLayout),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// walkTables calls fn for csv files of the directory of the table location and its subdirectories.
+// Hidden files and directories are skipped.
+func (db *DB) walkTables(ctx context.Context, root string, fn func(name string, info fs.FileInfo)) error {
+	dirs := []string{root}
+
+	for len(dirs) > 0 {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		dir := dirs[0]
+		dirs = dirs[1:]
+
+		infos, err := db.connector.List(path.Join(db.config.TableLocation, dir))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrTableConnection, err)
+		}
+
+		for _, info := range infos {
+			name := path.Join(dir, info.Name())
+			switch {
+			case strings.HasPrefix(info.Name(), "."):
+			case info.IsDir():
+				dirs = append(dirs, name)
+			case isTableFile(name):
+				fn(name, info)
+			}
+		}
+	}
+
+	return nil
+}
+
+// columnStats collects statistics of the sampled column values.
+type columnStats struct {
+	name    string
+	values  int
+	nulls   int
+	example string
+	typ     string
+}
+
+// add adds the sampled value to the statistics.
+func (s *columnStats) add(value string) {
+	s.values++
+	if value == "" {
+		s.nulls++
+		return
+	}
+
+	if s.example == "" {
+		s.example = value
+	}
+
+	s.typ = widenType(s.typ, valueType(value))
+}
+
+func (s *columnStats) row() []string {
+	typ := s.typ
+	if typ == "" {
+		typ = typeUnknown
+	}
+
+	var nullRatio float64
+	if s.values > 0 {
+		nullRatio = float64(s.nulls) / float64(s.values)
+	}
+
+	return []string{s.name, typ, strconv.FormatFloat(nullRatio, 'f', 2, 64), s.example}
+}
+
+// describeTable describes columns of the table by its header and the first rows.
+func (db *DB) describeTable(ctx context.Context, name csvquery.Table) ([][]string, error) {
+	table := NewTable(name, &csvquery.Query{}, db)
+	if !table.Exists() {
+		return nil, fmt.Errorf("%w: table '%s' doesn't exist", csvquery.ErrIncorrectQuery, name)
+	}
+
+	source, err := table.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := table.connection.Close()
+		if err != nil {
+			db.logger.Error(fmt.Errorf("%w: '%s', Real Error: %v", ErrTableDisconnection, name, err).Error())
+		}
+	}()
+
+	header, err := source.readHeader()
+	if err != nil {
+		db.logger.Error(err.Error())
+		return nil, fmt.Errorf("%w: '%s'", ErrTableColumnsRead, name)
+	}
+
+	stats := make([]columnStats, len(header))
+	for i, column := range header {
+		stats[i].name = column
+	}
+
+	addRow := func(row []string) {
+		table.normalizeRow(row)
+		for i := range stats {
+			if i < len(row) {
+				stats[i].add(row[i])
+			}
+		}
+	}
+
+	err = sampleRows(ctx, source, db.config.ParseMode == config.ParseModeLenient, addRow)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(stats))
+	for i := range stats {
+		if typ := table.options.ColumnType(stats[i].name); typ != "" {
+			stats[i].typ = typ
+		}
+		rows = append(rows, stats[i].row())
+	}
+
+	return rows, nil
+}
+
+// sampleRows calls fn for the first rows of the table, malformed rows are skipped if lenient is true.
+func sampleRows(ctx context.Context, source rowSource, lenient bool, fn func(row []string)) error {
+	for i := 0; i < describeSampleRows; i++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		row, err := source.read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *rowParseError
+		if lenient && errors.As(err, &parseErr) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrIncorrectTableRow, err)
+		}
+
+		fn(row)
+	}
+
+	return nil
+}
+
+// valueType returns the narrowest type of the value.
+func valueType(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return typeInteger
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return typeNumber
+	}
+
+	return typeString
+}
+
+// widenType returns the narrowest type which contains values of both types.
+func widenType(current, next string) string {
+	switch {
+	case current == "" || current == next:
+		return next
+	case current == typeString || next == typeString:
+		return typeString
+	default:
+		return typeNumber
+	}
+}
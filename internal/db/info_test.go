@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+func TestDB_showTables(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte("id\n1\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("text"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden.csv"), []byte("id\n"), 0600))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "archive"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "archive", "users2020.CSV"), []byte("id\n"), 0600))
+
+	db := NewDB(FileTableConnector{}, &csvquery.Query{}, zap.NewNop(), &config.Config{TableLocation: dir})
+	rows, err := db.showTables(context.Background())
+	assert.NoError(t, err)
+
+	var tables []string
+	for _, row := range rows {
+		tables = append(tables, row[0])
+	}
+	assert.Equal(t, []string{"users.csv", "archive/users2020.CSV"}, tables)
+	assert.Equal(t, "5", rows[0][1])
+}
+
+func TestDB_describeTable(t *testing.T) {
+	dir := t.TempDir()
+	content := "id,price,name,comment\n1,10,Bob,\n2,10.5,,\n3,7,42,\n,1,Ann,\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte(content), 0600))
+
+	db := NewDB(FileTableConnector{}, &csvquery.Query{}, zap.NewNop(), &config.Config{TableLocation: dir, FieldDelimiter: ',', Header: true})
+	rows, err := db.describeTable(context.Background(), "users.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", typeInteger, "0.25", "1"},
+		{"price", typeNumber, "0.00", "10"},
+		{"name", typeString, "0.25", "Bob"},
+		{"comment", typeUnknown, "1.00", ""},
+	}, rows)
+
+	_, err = db.describeTable(context.Background(), "roles.csv")
+	assert.ErrorIs(t, err, csvquery.ErrIncorrectQuery)
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// jsonSampleRows is the number of the first rows of a JSON Lines table which columns are collected from.
+const jsonSampleRows = 1000
+
+// errJSONObjectExpected indicates that a line of a JSON Lines table isn't an object.
+var errJSONObjectExpected = errors.New("JSON object expected")
+
+// jsonField is a flattened field of a JSON object, nested fields are named with dotted paths.
+type jsonField struct {
+	name  string
+	value string
+}
+
+// jsonLine is a parsed line of a JSON Lines table or the error of a malformed line.
+type jsonLine struct {
+	fields []jsonField
+	err    error
+}
+
+// jsonSource reads rows of a JSON Lines table, every line is an object.
+// Columns are fields of the first rows in order of appearance, fields which appear later are ignored.
+type jsonSource struct {
+	lines lineReader
+
+	columns []string
+	index   map[string]int
+
+	// sampled contains lines read while collecting columns, err is the error which stopped sampling.
+	sampled []jsonLine
+	err     error
+}
+
+func newJSONSource(reader *bufio.Reader) *jsonSource {
+	return &jsonSource{
+		lines: lineReader{reader: reader},
+		index: make(map[string]int),
+	}
+}
+
+func (s *jsonSource) readHeader() ([]string, error) {
+	for len(s.sampled) < jsonSampleRows {
+		fields, err := s.readLine()
+		var parseErr *rowParseError
+		if errors.As(err, &parseErr) {
+			s.sampled = append(s.sampled, jsonLine{err: err})
+			continue
+		}
+		if err != nil {
+			s.err = err
+			break
+		}
+
+		for _, field := range fields {
+			if _, ok := s.index[field.name]; !ok {
+				s.index[field.name] = len(s.columns)
+				s.columns = append(s.columns, field.name)
+			}
+		}
+		s.sampled = append(s.sampled, jsonLine{fields: fields})
+	}
+
+	if len(s.columns) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, errJSONObjectExpected
+	}
+
+	return s.columns, nil
+}
+
+func (s *jsonSource) read() ([]string, error) {
+	if len(s.sampled) > 0 {
+		line := s.sampled[0]
+		s.sampled = s.sampled[1:]
+		if line.err != nil {
+			return nil, line.err
+		}
+		return s.row(line.fields), nil
+	}
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	fields, err := s.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.row(fields), nil
+}
+
+// row returns values of the fields in the column order, missing fields are empty.
+func (s *jsonSource) row(fields []jsonField) []string {
+	row := make([]string, len(s.columns))
+	for _, field := range fields {
+		if i, ok := s.index[field.name]; ok {
+			row[i] = field.value
+		}
+	}
+
+	return row
+}
+
+// readLine returns fields of the next non-empty line.
+func (s *jsonSource) readLine() ([]jsonField, error) {
+	for {
+		line, err := s.lines.next()
+		if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields, parseErr := flattenJSON([]byte(line))
+		if parseErr != nil {
+			return nil, &rowParseError{line: s.lines.line, err: parseErr}
+		}
+
+		return fields, nil
+	}
+}
+
+// flattenJSON returns fields of the JSON object, nested objects are flattened to fields like user.id.
+func flattenJSON(data []byte) ([]jsonField, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	var fields []jsonField
+	err := flattenObject(decoder, "", &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after JSON object")
+	}
+
+	return fields, nil
+}
+
+func flattenObject(decoder *json.Decoder, prefix string, fields *[]jsonField) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return errJSONObjectExpected
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		name := prefix + token.(string)
+
+		var raw json.RawMessage
+		err = decoder.Decode(&raw)
+		if err != nil {
+			return err
+		}
+
+		if raw[0] == '{' {
+			err = flattenObject(json.NewDecoder(bytes.NewReader(raw)), name+".", fields)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := jsonValue(raw)
+		if err != nil {
+			return err
+		}
+		*fields = append(*fields, jsonField{name: name, value: value})
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+// jsonValue returns the text of a scalar value, null is empty and arrays are compact JSON.
+func jsonValue(raw json.RawMessage) (string, error) {
+	switch raw[0] {
+	case '"':
+		var value string
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	case 'n':
+		return "", nil
+	case '[':
+		var buf bytes.Buffer
+		err := json.Compact(&buf, raw)
+		return buf.String(), err
+	}
+
+	return string(raw), nil
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/config"
+)
+
+func TestFlattenJSON(t *testing.T) {
+	fields, err := flattenJSON([]byte(`{"id": 1, "user": {"name": "Bob", "address": { "city": "Paris" }}, "tags": [ "a", "b" ], "ok": true, "note": null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []jsonField{
+		{name: "id", value: "1"},
+		{name: "user.name", value: "Bob"},
+		{name: "user.address.city", value: "Paris"},
+		{name: "tags", value: `["a","b"]`},
+		{name: "ok", value: "true"},
+		{name: "note", value: ""},
+	}, fields)
+
+	for _, line := range []string{`[1, 2]`, `{"id": 1`, `{"id": 1} {"id": 2}`, `"id"`} {
+		_, err = flattenJSON([]byte(line))
+		assert.Error(t, err, line)
+	}
+}
+
+func TestJSONSource(t *testing.T) {
+	content := "{\"id\": 1, \"user\": {\"id\": 10}}\n\n{\"id\": 2, \"extra\": \"x\"}\nnot json\n{\"user\": {\"id\": 30}}"
+	source := newJSONSource(bufio.NewReader(strings.NewReader(content)))
+
+	header, err := source.readHeader()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "user.id", "extra"}, header)
+
+	var rows [][]string
+	var parseErr *rowParseError
+	for {
+		row, err := source.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			assert.ErrorAs(t, err, &parseErr)
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	assert.Equal(t, [][]string{{"1", "10", ""}, {"2", "", "x"}, {"", "30", ""}}, rows)
+	assert.Equal(t, 4, parseErr.line)
+
+	_, err = newJSONSource(bufio.NewReader(strings.NewReader(""))).readHeader()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestTableFormat(t *testing.T) {
+	assert.Equal(t, catalog.FormatCSV, tableFormat("users.csv", catalog.TableOptions{}))
+	assert.Equal(t, catalog.FormatCSV, tableFormat("users", catalog.TableOptions{}))
+	assert.Equal(t, catalog.FormatJSONLines, tableFormat("events.jsonl", catalog.TableOptions{}))
+	assert.Equal(t, catalog.FormatJSONLines, tableFormat("events.NDJSON.gz", catalog.TableOptions{}))
+	assert.Equal(t, catalog.FormatJSONLines, tableFormat("events.log", catalog.TableOptions{Format: catalog.FormatJSONLines}))
+	assert.Equal(t, catalog.FormatTSV, tableFormat("notes.tsv", catalog.TableOptions{}))
+	assert.Equal(t, catalog.FormatFixedWidth, tableFormat("accounts.dat", catalog.TableOptions{Layout: []catalog.FixedColumn{{Name: "id", Start: 1, Length: 6}}}))
+}
+
+func TestExecute_jsonLines(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{
+		"events.jsonl": `{"type": "login", "user": {"id": 7, "name": "Bob"}}
+{"type": "logout", "user": {"id": 7, "name": "Bob"}}
+{"type": "login", "user": {"id": 9, "name": "Ann \"A\""}}
+`,
+	})
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+	}
+
+	err := Execute(context.Background(), FileTableConnector{}, "select user.name, _file from events.jsonl where type = 'login' and user.id > 5 into outfile 'logins.csv'", conf, zap.NewNop())
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "logins.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "user.name,_file\nBob,events.jsonl\n\"Ann \"\"A\"\"\",events.jsonl\n", string(content))
+}
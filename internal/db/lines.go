@@ -0,0 +1,40 @@
+package db
+
+import (
+	"bufio"
+	"strings"
+)
+
+// lineReader reads lines of a text table and counts them.
+type lineReader struct {
+	reader *bufio.Reader
+	line   int
+}
+
+// next returns the next line without the line break, io.EOF after the last line.
+func (r *lineReader) next() (string, error) {
+	line, err := r.reader.ReadString('\n')
+	if len(line) == 0 && err != nil {
+		return "", err
+	}
+	r.line++
+
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+// nextRecord returns the next line which is neither empty nor starts with the comment character.
+func (r *lineReader) nextRecord(comment rune) (string, error) {
+	for {
+		line, err := r.next()
+		if err != nil {
+			return "", err
+		}
+
+		if line == "" || comment != 0 && strings.HasPrefix(line, string(comment)) {
+			continue
+		}
+
+		return line, nil
+	}
+}
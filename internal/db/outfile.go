@@ -0,0 +1,120 @@
+package db
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/phpCoder88/csv-searcher/internal/output"
+)
+
+var (
+	// ErrOutFileExists indicates that the output file already exists.
+	ErrOutFileExists = errors.New("output file already exists")
+	// ErrOutFilePath indicates that the output file is outside of the table location.
+	ErrOutFilePath = errors.New("output file must be inside the table location")
+	// ErrOutFileWrite indicates that executor can't write the output file.
+	ErrOutFileWrite = errors.New("can't write output file")
+)
+
+// outFileMode is the mode of output files, the temporary file is created readable only by the owner.
+const outFileMode = 0644
+
+// fileSink atomically writes query result to the output file.
+// Rows are written to a temporary file in the same directory which is linked to the output file on Close,
+// linking fails if the output file is created meanwhile.
+type fileSink struct {
+	output.Writer
+	buf      *bufio.Writer
+	tmpFile  *os.File
+	filePath string
+	header   bool
+}
+
+func (s *fileSink) WriteHeader(columns []string) error {
+	if !s.header {
+		return nil
+	}
+
+	return s.Writer.WriteHeader(columns)
+}
+
+func (s *fileSink) Close() error {
+	err := s.Writer.Close()
+	if err == nil {
+		err = s.buf.Flush()
+	}
+	if err == nil {
+		err = s.tmpFile.Chmod(outFileMode)
+	}
+	if closeErr := s.tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Link(s.tmpFile.Name(), s.filePath)
+		if errors.Is(err, fs.ErrExist) {
+			err = fmt.Errorf("%w: '%s'", ErrOutFileExists, s.filePath)
+		}
+	}
+	_ = os.Remove(s.tmpFile.Name())
+
+	return err
+}
+
+func (s *fileSink) Abort() {
+	_ = s.tmpFile.Close()
+	_ = os.Remove(s.tmpFile.Name())
+}
+
+// openFileSink creates the temporary file for the output file of the query.
+func (db *DB) openFileSink() (resultSink, error) {
+	outFile := db.query.Into
+
+	filePath, err := db.outFilePath(outFile.Path)
+	if err != nil {
+		db.logger.Error(err.Error())
+		return nil, err
+	}
+
+	if db.connector.Exists(filePath) {
+		err = fmt.Errorf("%w: '%s'", ErrOutFileExists, outFile.Path)
+		db.logger.Error(err.Error())
+		return nil, err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		userErr := fmt.Errorf("%w: '%s'", ErrOutFileWrite, outFile.Path)
+		db.logger.Error(fmt.Errorf("%w, Real Error: %v", userErr, err).Error())
+		return nil, userErr
+	}
+
+	delimiter := outFile.Delimiter
+	if delimiter == 0 {
+		delimiter = db.config.FieldDelimiter
+	}
+
+	buf := bufio.NewWriter(tmpFile)
+	return &fileSink{
+		Writer:   output.NewDelimitedWriter(buf, delimiter, outFile.Quote),
+		buf:      buf,
+		tmpFile:  tmpFile,
+		filePath: filePath,
+		header:   outFile.Header,
+	}, nil
+}
+
+// outFilePath returns the output file path inside the table location.
+func (db *DB) outFilePath(name string) (string, error) {
+	filePath := filepath.Join(db.config.TableLocation, name)
+	rel, err := filepath.Rel(db.config.TableLocation, filePath)
+	if filepath.IsAbs(name) || err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: '%s'", ErrOutFilePath, name)
+	}
+
+	return filePath, nil
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+func TestDB_outFilePath(t *testing.T) {
+	db := &DB{config: &config.Config{TableLocation: "./tables"}}
+
+	filePath, err := db.outFilePath("result.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("tables", "result.csv"), filePath)
+
+	filePath, err = db.outFilePath("export/../result.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("tables", "result.csv"), filePath)
+
+	for _, name := range []string{"../result.csv", "/tmp/result.csv", ".."} {
+		_, err = db.outFilePath(name)
+		assert.ErrorIs(t, err, ErrOutFilePath, name)
+	}
+}
+
+func TestExecute_outFileIgnoresLimit(t *testing.T) {
+	dir := t.TempDir()
+	var content strings.Builder
+	content.WriteString("id\n")
+	for i := 1; i <= 250; i++ {
+		fmt.Fprintf(&content, "%d\n", i)
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte(content.String()), 0600))
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+		Limit:          100,
+	}
+
+	for _, statement := range []string{
+		"select id from users.csv into outfile 'into.csv'",
+		"copy (select id from users.csv) to 'copy.csv'",
+	} {
+		query := csvquery.NewQuery(statement, zap.NewNop())
+		db := NewDB(FileTableConnector{}, query, zap.NewNop(), conf)
+		rowCount, err := db.run(context.Background(), context.Background())
+		assert.NoError(t, err, statement)
+		assert.Equal(t, 250, rowCount, statement)
+
+		written, err := os.ReadFile(filepath.Join(dir, query.Into.Path))
+		assert.NoError(t, err, statement)
+		assert.Equal(t, content.String(), string(written), statement)
+	}
+
+	query := csvquery.NewQuery("select id from users.csv", zap.NewNop())
+	db := NewDB(FileTableConnector{}, query, zap.NewNop(), conf)
+	assert.Equal(t, int32(100), db.limit())
+}
+
+func TestFileSink_Close(t *testing.T) {
+	dir := t.TempDir()
+	conf := &config.Config{TableLocation: dir, FieldDelimiter: ','}
+	query := csvquery.NewQuery("select id from users.csv into outfile 'result.csv'", zap.NewNop())
+	assert.NoError(t, query.Parse())
+	db := NewDB(FileTableConnector{}, query, zap.NewNop(), conf)
+
+	sink, err := db.openFileSink()
+	assert.NoError(t, err)
+	assert.NoError(t, sink.WriteHeader([]string{"id"}))
+	assert.NoError(t, sink.WriteRow([]string{"1"}))
+
+	// the output file is created by another process while the query runs
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "result.csv"), []byte("kept\n"), 0600))
+
+	err = sink.Close()
+	assert.ErrorIs(t, err, ErrOutFileExists)
+
+	content, err := os.ReadFile(filepath.Join(dir, "result.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "kept\n", string(content))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.NoError(t, os.Remove(filepath.Join(dir, "result.csv")))
+	sink, err = db.openFileSink()
+	assert.NoError(t, err)
+	assert.NoError(t, sink.WriteRow([]string{"1"}))
+	assert.NoError(t, sink.Close())
+
+	info, err := os.Stat(filepath.Join(dir, "result.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(outFileMode), info.Mode().Perm())
+}
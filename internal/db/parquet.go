@@ -0,0 +1,274 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+	"github.com/phpCoder88/csv-searcher/internal/parquet"
+)
+
+// randomAccessFile is a table file which can be read at any offset.
+type randomAccessFile interface {
+	io.ReaderAt
+	Stat() (fs.FileInfo, error)
+}
+
+// openParquet opens the Parquet table. Uncompressed files are read at the offsets of the needed column chunks,
+// other tables are read to memory.
+func (t *Table) openParquet(file io.ReadCloser) (rowSource, error) {
+	var reader io.ReaderAt
+	var size int64
+
+	if f, ok := file.(*decompressedFile); ok && !f.compressed {
+		if raf, ok := f.file.(randomAccessFile); ok {
+			info, err := raf.Stat()
+			if err != nil {
+				_ = t.connection.Close()
+				return nil, fmt.Errorf("%w: %s: %v", ErrTableConnection, t.name, err)
+			}
+			reader, size = &countingReaderAt{ReaderAt: raf, count: &t.stats.bytesRead}, info.Size()
+		}
+	}
+
+	if reader == nil {
+		data, err := io.ReadAll(t.connection)
+		if err != nil {
+			_ = t.connection.Close()
+			return nil, fmt.Errorf("%w: %s: %v", ErrTableConnection, t.name, err)
+		}
+		reader, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	pq, err := parquet.Open(reader, size)
+	if err != nil {
+		_ = t.connection.Close()
+		return nil, fmt.Errorf("%w: %s: %v", ErrTableConnection, t.name, err)
+	}
+
+	return &parquetSource{table: t, file: pq, group: -1}, nil
+}
+
+// parquetSource reads rows of a Parquet table row group by row group.
+// Only the columns used by the query are read, values of other columns are empty.
+// Row groups whose statistics show that no row satisfies the WHERE condition are skipped.
+type parquetSource struct {
+	table *Table
+	file  *parquet.File
+	// projection contains indexes of the read columns, it's nil until the first read.
+	projection []int
+	// group is the index of the current row group, columns are readers of its projected columns.
+	group   int
+	columns []*parquet.ColumnReader
+	// rows is the number of rows left in the current row group.
+	rows int64
+}
+
+func (s *parquetSource) readHeader() ([]string, error) {
+	columns := make([]string, len(s.file.Columns))
+	for i := range s.file.Columns {
+		columns[i] = s.file.Columns[i].Name
+	}
+
+	return columns, nil
+}
+
+func (s *parquetSource) read() ([]string, error) {
+	if s.projection == nil {
+		s.projection = s.table.projection(len(s.file.Columns))
+	}
+
+	for s.rows == 0 {
+		err := s.nextGroup()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	row := make([]string, len(s.file.Columns))
+	for i, reader := range s.columns {
+		value, err := reader.Next()
+		if err == io.EOF {
+			err = fmt.Errorf("%w: row group %d has less values than rows", parquet.ErrInvalidFile, s.group)
+		}
+		if err != nil {
+			return nil, err
+		}
+		row[s.projection[i]] = value
+	}
+	s.rows--
+
+	return row, nil
+}
+
+// nextGroup opens the projected columns of the next row group which may have matching rows, io.EOF after the last one.
+func (s *parquetSource) nextGroup() error {
+	for s.group+1 < len(s.file.RowGroups) {
+		s.group++
+		group := &s.file.RowGroups[s.group]
+		atomic.AddInt64(&s.table.stats.rowGroups, 1)
+
+		if s.table.query.Where != nil && !s.groupMayMatch(group, s.table.query.Where) {
+			atomic.AddInt64(&s.table.stats.rowGroupsSkipped, 1)
+			continue
+		}
+
+		s.columns = make([]*parquet.ColumnReader, len(s.projection))
+		for i, column := range s.projection {
+			reader, err := group.Column(column)
+			if err != nil {
+				return err
+			}
+			s.columns[i] = reader
+		}
+		s.rows = group.NumRows
+
+		return nil
+	}
+
+	return io.EOF
+}
+
+// projection returns sorted indexes of the table columns used by the query,
+// all columns are used by SELECT * and by statements without a column list.
+func (t *Table) projection(count int) []int {
+	all := len(t.query.Select) == 0
+	for _, column := range t.query.Select {
+		if column == "*" {
+			all = true
+		}
+	}
+
+	indexes := make([]int, 0, count)
+	if all {
+		for i := 0; i < count; i++ {
+			indexes = append(indexes, i)
+		}
+		return indexes
+	}
+
+	used := make(map[int]bool, len(t.mapColumns))
+	for _, index := range t.mapColumns {
+		// the _file column isn't stored in the file
+		if index < count && !used[index] {
+			used[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+
+	return indexes
+}
+
+// groupMayMatch returns false if the statistics of the row group show that no row satisfies the expression.
+func (s *parquetSource) groupMayMatch(group *parquet.RowGroup, node csvquery.Expr) bool {
+	switch expr := node.(type) {
+	case *csvquery.BinaryExpr:
+		if expr.Op == csvquery.AndOperator {
+			return s.groupMayMatch(group, expr.Left) && s.groupMayMatch(group, expr.Right)
+		}
+		return s.groupMayMatch(group, expr.Left) || s.groupMayMatch(group, expr.Right)
+	case *csvquery.Condition:
+		return s.conditionMayMatch(group, expr)
+	}
+
+	return true
+}
+
+// conditionMayMatch returns false if the column statistics show that no value of the row group satisfies the condition.
+// Values are compared as rows are: numbers as numbers and strings byte by byte, null values are empty.
+func (s *parquetSource) conditionMayMatch(group *parquet.RowGroup, cond *csvquery.Condition) bool {
+	t := s.table
+	index, ok := t.mapColumns[cond.Column]
+	if !ok || index >= len(s.file.Columns) || t.options.ColumnType(string(cond.Column)) == catalog.TypeDate {
+		return true
+	}
+
+	column := &s.file.Columns[index]
+	stats := group.Stats(index)
+	allNull := stats.HasNullCount && stats.NullCount == group.NumRows
+
+	switch value := cond.Value.(type) {
+	case csvquery.NumberLiteral:
+		if column.Kind() != parquet.KindNumber {
+			return true
+		}
+		if allNull {
+			// empty values never satisfy number conditions
+			return false
+		}
+		if !stats.HasMinMax {
+			return true
+		}
+
+		lo, errMin := strconv.ParseFloat(stats.Min, 64)
+		hi, errMax := strconv.ParseFloat(stats.Max, 64)
+		if errMin != nil || errMax != nil || math.IsNaN(lo) || math.IsNaN(hi) {
+			return true
+		}
+
+		v := float64(value)
+		return rangeMayMatch(cond.Op, compareFloats(v, lo), compareFloats(v, hi))
+	case csvquery.StringLiteral:
+		if column.Kind() != parquet.KindText {
+			return true
+		}
+		if allNull {
+			result, err := cond.CheckCondition("")
+			return result || err != nil
+		}
+		if !stats.HasMinMax {
+			return true
+		}
+
+		lo := stats.Min
+		hasNulls := stats.NullCount > 0 || !stats.HasNullCount && column.Nullable()
+		if hasNulls || len(t.options.Null) > 0 {
+			// null values are read as empty strings
+			lo = ""
+		}
+
+		v := string(value)
+		return rangeMayMatch(cond.Op, strings.Compare(v, lo), strings.Compare(v, stats.Max))
+	}
+
+	return true
+}
+
+// rangeMayMatch returns true if a value between the minimum and the maximum may satisfy the comparison
+// with the condition value, toMin and toMax are results of comparing the condition value with them.
+func rangeMayMatch(op csvquery.ComparisonOperator, toMin, toMax int) bool {
+	switch op {
+	case csvquery.EqualOperator:
+		return toMin >= 0 && toMax <= 0
+	case csvquery.LessOperator:
+		return toMin > 0
+	case csvquery.LessOrEqualOperator:
+		return toMin >= 0
+	case csvquery.GreaterOperator:
+		return toMax < 0
+	case csvquery.GreaterOrEqualOperator:
+		return toMax <= 0
+	}
+
+	return true
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+	"github.com/phpCoder88/csv-searcher/internal/parquet/parquettest"
+)
+
+// createParquet returns a Parquet file of events in row groups of two rows.
+func createParquet(t *testing.T, options parquettest.Options) string {
+	t.Helper()
+
+	columns := []parquettest.Column{
+		{Name: "id", Type: parquettest.Int64},
+		{Name: "name", Type: parquettest.String, Optional: true},
+		{Name: "score", Type: parquettest.Double, Optional: true},
+		{Name: "active", Type: parquettest.Boolean},
+		{Name: "born", Type: parquettest.Date, Optional: true},
+	}
+	rows := [][]interface{}{
+		{int64(1), "Alice", 4.5, true, int32(18993)},
+		{int64(2), nil, nil, false, nil},
+		{int64(3), "Bob", -1.25, true, int32(0)},
+		{int64(4), "Alice", 10.0, false, int32(-1)},
+		{int64(5), "", 0.0, true, nil},
+	}
+
+	options.RowGroupSize = 2
+	data, err := parquettest.Write(columns, rows, options)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return string(data)
+}
+
+func TestExecute_parquet(t *testing.T) {
+	dir := t.TempDir()
+	events := createParquet(t, parquettest.Options{Codec: parquettest.Snappy})
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte(createParquet(t, parquettest.Options{Dictionary: true})))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	createTables(t, dir, map[string]string{
+		"events.parquet":          events,
+		"archive/2020.parquet.gz": compressed.String(),
+		"archive/history.dat":     events,
+		catalog.FileName:          "tables:\n  archive/history.dat:\n    format: parquet\n",
+	})
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			query: "select name, score from events.parquet where score > 4",
+			want:  "name,score\nAlice,4.5\nAlice,10\n",
+		},
+		{
+			query: "select * from events.parquet where name = 'Bob' or id = 2",
+			want:  "id,name,score,active,born\n2,,,false,\n3,Bob,-1.25,true,1970-01-01\n",
+		},
+		{
+			query: "select id, _file from events.parquet, 'archive/*.parquet.gz' where active = 'true' and id > 2",
+			want:  "id,_file\n3,events.parquet\n5,events.parquet\n3,archive/2020.parquet.gz\n5,archive/2020.parquet.gz\n",
+		},
+		{
+			query: "select id, born from archive/history.dat where born < '1970-01-02'",
+			want:  "id,born\n2,\n3,1970-01-01\n4,1969-12-31\n5,\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			err := Execute(context.Background(), FileTableConnector{}, tt.query+" into outfile 'result.csv'", conf, zap.NewNop())
+			assert.NoError(t, err)
+
+			content, err := os.ReadFile(filepath.Join(dir, "result.csv"))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(content))
+			assert.NoError(t, os.Remove(filepath.Join(dir, "result.csv")))
+		})
+	}
+
+	createTables(t, dir, map[string]string{"broken.parquet": "id,name\n1,Bob\n"})
+	err = Execute(context.Background(), FileTableConnector{}, "select id from broken.parquet", conf, zap.NewNop())
+	assert.ErrorIs(t, err, ErrTableConnection)
+
+	db := NewDB(FileTableConnector{}, &csvquery.Query{}, zap.NewNop(), conf)
+	rows, err := db.describeTable(context.Background(), "events.parquet")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", typeInteger, "0.00", "1"},
+		{"name", typeString, "0.40", "Alice"},
+		{"score", typeNumber, "0.20", "4.5"},
+		{"active", typeString, "0.00", "true"},
+		{"born", typeString, "0.40", "2022-01-01"},
+	}, rows)
+}
+
+func TestDB_runAnalyze_parquet(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{"events.parquet": createParquet(t, parquettest.Options{})})
+
+	conf := &config.Config{TableLocation: dir, Workers: 2, FieldDelimiter: ',', Header: true, Ordered: true}
+	run := func(statement string) *tableStats {
+		query := csvquery.NewQuery(statement, zap.NewNop())
+		db := NewDB(FileTableConnector{}, query, zap.NewNop(), conf)
+		db.analyze = true
+
+		_, err := db.run(context.Background(), context.Background())
+		assert.NoError(t, err, statement)
+		assert.Len(t, db.tables, 1)
+
+		return &db.tables[0].stats
+	}
+
+	tests := []struct {
+		where   string
+		skipped int64
+	}{
+		{where: "id > 3", skipped: 1},
+		{where: "id = 10", skipped: 3},
+		{where: "id < 2 or id > 4", skipped: 1},
+		{where: "id >= 2 and id <= 3", skipped: 1},
+		{where: "id != 1", skipped: 0},
+		{where: "score > 100", skipped: 3},
+		{where: "score <= -2 or id = 5", skipped: 2},
+		{where: "name = 'Bob'", skipped: 2},
+		{where: "name < 'B'", skipped: 0},
+		{where: "name > 'Bob'", skipped: 3},
+		{where: "born = '2022-01-01'", skipped: 2},
+		{where: "born = ''", skipped: 1},
+		{where: "active = 'false'", skipped: 1},
+		{where: "_file = 'events.parquet' and id > 4", skipped: 2},
+	}
+
+	for _, tt := range tests {
+		stats := run("select id from events.parquet where " + tt.where)
+		assert.Equal(t, int64(3), stats.rowGroups, tt.where)
+		assert.Equal(t, tt.skipped, stats.rowGroupsSkipped, tt.where)
+	}
+
+	stats := run("select id from events.parquet where id > 3")
+	assert.Equal(t, int64(3), stats.rowsScanned)
+	assert.Equal(t, int64(2), stats.rowsMatched)
+	assert.Contains(t, stats.lines(""), "Row groups: 2 read, 1 skipped")
+
+	projected := run("select id from events.parquet").bytesRead
+	all := run("select * from events.parquet").bytesRead
+	assert.Less(t, projected, all)
+}
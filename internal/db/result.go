@@ -0,0 +1,73 @@
+package db
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/phpCoder88/csv-searcher/internal/output"
+)
+
+// resultSink writes query result rows as they arrive.
+// Close finishes the result, Abort discards it after an error.
+type resultSink interface {
+	output.Writer
+	Abort()
+}
+
+// stdoutSink writes query result to stdout in the configured format.
+type stdoutSink struct {
+	output.Writer
+	buf *bufio.Writer
+}
+
+func (s *stdoutSink) Close() error {
+	if err := s.Writer.Close(); err != nil {
+		return err
+	}
+
+	return s.buf.Flush()
+}
+
+func (s *stdoutSink) Abort() {
+	_ = s.buf.Flush()
+}
+
+// openSink returns the sink for the query result.
+func (db *DB) openSink() (resultSink, error) {
+	if db.query.Into != nil {
+		return db.openFileSink()
+	}
+
+	if db.analyze {
+		return discardSink{}, nil
+	}
+
+	buf := bufio.NewWriter(os.Stdout)
+	format := output.Format(db.config.Format)
+	if format == output.FormatTable {
+		return &stdoutSink{Writer: output.NewTableWriter(buf, db.config.PreviewRows), buf: buf}, nil
+	}
+
+	writer, err := output.NewWriter(format, buf)
+	if err != nil {
+		db.logger.Error(err.Error())
+		return nil, err
+	}
+
+	return &stdoutSink{Writer: writer, buf: buf}, nil
+}
+
+// discardSink discards the query result.
+type discardSink struct{}
+
+func (discardSink) WriteHeader([]string) error { return nil }
+func (discardSink) WriteRow([]string) error    { return nil }
+func (discardSink) Close() error               { return nil }
+func (discardSink) Abort()                     {}
+
+func (db *DB) sinkError(err error) error {
+	err = fmt.Errorf("%w: %v", ErrResultWrite, err)
+	db.logger.Error(err.Error())
+	return err
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+)
+
+const (
+	// sniffSize is the size of the table beginning inspected to detect the dialect.
+	sniffSize = 16 * 1024
+	// sniffLines is the maximum number of lines inspected to detect the dialect.
+	sniffLines = 50
+)
+
+// utf8BOM is the byte order mark some editors write at the beginning of UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// sniffDelimiters lists delimiters which are detected.
+var sniffDelimiters = []rune{',', ';', '\t', '|'}
+
+// dialect describes the table format detected by the table beginning.
+// Zero values mean the format option isn't detected.
+type dialect struct {
+	delimiter rune
+	quote     byte
+	// noHeader is true if the first line looks like a data row.
+	noHeader bool
+}
+
+// skipBOM skips UTF-8 byte order mark at the beginning of the reader.
+func skipBOM(reader *bufio.Reader) {
+	prefix, err := reader.Peek(len(utf8BOM))
+	if err == nil && bytes.Equal(prefix, utf8BOM) {
+		_, _ = reader.Discard(len(utf8BOM))
+	}
+}
+
+// sniffDialect detects the delimiter, the quote character and whether there is a header by the table beginning.
+func sniffDialect(sample []byte, full bool) dialect {
+	lines := sampleLines(sample, full)
+	if len(lines) == 0 {
+		return dialect{}
+	}
+
+	var d dialect
+	d.quote = sniffQuote(lines)
+	d.delimiter = sniffDelimiter(lines, d.quote)
+
+	delimiter := d.delimiter
+	if delimiter == 0 {
+		delimiter = ','
+	}
+	d.noHeader = !sniffHeader(lines, delimiter, d.quote)
+
+	return d
+}
+
+// sampleLines returns non-empty lines of the sample, the last line is skipped if the sample is cut.
+func sampleLines(sample []byte, full bool) []string {
+	lines := strings.Split(strings.ReplaceAll(string(sample), "\r\n", "\n"), "\n")
+	if !full && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			result = append(result, line)
+		}
+		if len(result) == sniffLines {
+			break
+		}
+	}
+
+	return result
+}
+
+// sniffQuote returns single quote if only it encloses fields, double quote otherwise.
+func sniffQuote(lines []string) byte {
+	single := countEnclosed(lines, '\'')
+	if single > 0 && countEnclosed(lines, '"') == 0 {
+		return '\''
+	}
+
+	return '"'
+}
+
+// countEnclosed counts fields which start and end with the quote.
+func countEnclosed(lines []string, quote byte) int {
+	isBoundary := func(line string, i int) bool {
+		return i < 0 || i >= len(line) || strings.ContainsRune(string(sniffDelimiters), rune(line[i]))
+	}
+
+	var count int
+	for _, line := range lines {
+		open := -1
+		for i := 0; i < len(line); i++ {
+			if line[i] != quote {
+				continue
+			}
+
+			if open == -1 && isBoundary(line, i-1) {
+				open = i
+				continue
+			}
+
+			if open != -1 && isBoundary(line, i+1) {
+				count++
+				open = -1
+			}
+		}
+	}
+
+	return count
+}
+
+// sniffDelimiter returns the delimiter which occurs the same number of times in the most lines
+// or zero if no delimiter is found.
+func sniffDelimiter(lines []string, quote byte) rune {
+	var best rune
+	var bestLines, bestCount int
+
+	for _, delimiter := range sniffDelimiters {
+		count := countOutside(lines[0], delimiter, quote)
+		if count == 0 {
+			continue
+		}
+
+		var consistent int
+		for _, line := range lines {
+			if countOutside(line, delimiter, quote) == count {
+				consistent++
+			}
+		}
+
+		if consistent > bestLines || (consistent == bestLines && count > bestCount) {
+			best, bestLines, bestCount = delimiter, consistent, count
+		}
+	}
+
+	return best
+}
+
+// countOutside counts the delimiter outside of quoted fields.
+func countOutside(line string, delimiter rune, quote byte) int {
+	var count int
+	var quoted bool
+	for _, char := range line {
+		switch {
+		case char == rune(quote):
+			quoted = !quoted
+		case char == delimiter && !quoted:
+			count++
+		}
+	}
+
+	return count
+}
+
+// sniffHeader returns false if the first line looks like a data row: it has a number in a column
+// where all other lines have numbers and none of its fields is text in such a column.
+// Column names like years are kept as a header when another name doesn't fit its column.
+func sniffHeader(lines []string, delimiter rune, quote byte) bool {
+	content := strings.Join(lines, "\n")
+	if quote != '"' {
+		swapped := []byte(content)
+		for i, char := range swapped {
+			switch char {
+			case quote:
+				swapped[i] = '"'
+			case '"':
+				swapped[i] = quote
+			}
+		}
+		content = string(swapped)
+	}
+
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil || len(records) < 2 {
+		return true
+	}
+
+	var dataLike bool
+	header := records[0]
+	for col := range header {
+		numeric := true
+		for _, record := range records[1:] {
+			if col >= len(record) || !isNumber(record[col]) {
+				numeric = false
+				break
+			}
+		}
+
+		if !numeric {
+			continue
+		}
+
+		if !isNumber(header[col]) {
+			return true
+		}
+		dataLike = true
+	}
+
+	return !dataLike
+}
+
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return err == nil
+}
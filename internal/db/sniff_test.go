@@ -0,0 +1,200 @@
+package db
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+func TestSniffDialect(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample string
+		full   bool
+		want   dialect
+	}{
+		{
+			name:   "comma",
+			sample: "id,name\n1,Bob\n2,Ann\n",
+			full:   true,
+			want:   dialect{delimiter: ',', quote: '"'},
+		},
+		{
+			name:   "semicolon with commas in values",
+			sample: "id;name;comment\n1;Bob;a, b\n2;Ann;c\n",
+			full:   true,
+			want:   dialect{delimiter: ';', quote: '"'},
+		},
+		{
+			name:   "tab",
+			sample: "id\tname\n1\tBob\n",
+			full:   true,
+			want:   dialect{delimiter: '\t', quote: '"'},
+		},
+		{
+			name:   "pipe with quoted delimiters",
+			sample: "id|name\n1|\"a|b|c\"\n2|d\n",
+			full:   true,
+			want:   dialect{delimiter: '|', quote: '"'},
+		},
+		{
+			name:   "single quote",
+			sample: "id;name\n1;'Smith; John'\n2;'Ann'\n",
+			full:   true,
+			want:   dialect{delimiter: ';', quote: '\''},
+		},
+		{
+			name:   "no header",
+			sample: "1,Bob,2021-01-01\n2,Ann,2021-02-01\n",
+			full:   true,
+			want:   dialect{delimiter: ',', quote: '"', noHeader: true},
+		},
+		{
+			name:   "numeric column names",
+			sample: "region,2020,2021\n1,10,12.5\n2,7,9\n",
+			full:   true,
+			want:   dialect{delimiter: ',', quote: '"'},
+		},
+		{
+			name:   "cut sample",
+			sample: "id;name\n1;Bob\n2;Ann\n3,4,5,6,7",
+			want:   dialect{delimiter: ';', quote: '"'},
+		},
+		{
+			name:   "one column",
+			sample: "name\nBob\n",
+			full:   true,
+			want:   dialect{quote: '"'},
+		},
+		{
+			name: "empty",
+			full: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sniffDialect([]byte(tt.sample), tt.full))
+		})
+	}
+}
+
+func TestSkipBOM(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("\xEF\xBB\xBFid,name\n"))
+	skipBOM(reader)
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,name\n", string(content))
+
+	reader = bufio.NewReader(strings.NewReader("id"))
+	skipBOM(reader)
+	content, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "id", string(content))
+}
+
+type stringConnector struct {
+	content string
+}
+
+func (c stringConnector) GetReader(string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(c.content)), nil
+}
+
+func (c stringConnector) Exists(string) bool {
+	return true
+}
+
+func (c stringConnector) IsDir(string) bool {
+	return false
+}
+
+func (c stringConnector) List(string) ([]fs.FileInfo, error) {
+	return nil, nil
+}
+
+func TestTable_connectSniff(t *testing.T) {
+	conf := &config.Config{FieldDelimiter: ',', Header: true, Sniff: true}
+	db := NewDB(stringConnector{content: "\xEF\xBB\xBFid;name\n1;Bob\n"}, &csvquery.Query{}, zap.NewNop(), conf)
+
+	table := NewTable("users.csv", db.query, db)
+	source, err := table.connect()
+	assert.NoError(t, err)
+
+	header, err := source.readHeader()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "name"}, header)
+
+	row, err := source.read()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "Bob"}, row)
+
+	conf.Sniff = false
+	table = NewTable("users.csv", db.query, db)
+	source, err = table.connect()
+	assert.NoError(t, err)
+
+	header, err = source.readHeader()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id;name"}, header)
+
+	// an explicit session delimiter isn't overridden by the detected one
+	conf.Sniff = true
+	conf.DelimiterSet = true
+	table = NewTable("users.csv", db.query, db)
+	source, err = table.connect()
+	assert.NoError(t, err)
+
+	header, err = source.readHeader()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id;name"}, header)
+}
+
+func TestTable_connectSniffHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		headerSet  bool
+		wantHeader []string
+	}{
+		{
+			name:       "numeric column names",
+			content:    "region,2020,2021\n1,10,12.5\n2,7,9\n",
+			wantHeader: []string{"region", "2020", "2021"},
+		},
+		{
+			name:       "data row",
+			content:    "north,2020,2021\nsouth,10,12.5\n",
+			wantHeader: []string{"c1", "c2", "c3"},
+		},
+		{
+			name:       "header set explicitly",
+			content:    "region,2020,2021\nnorth,10,12.5\nsouth,7,9\n",
+			headerSet:  true,
+			wantHeader: []string{"region", "2020", "2021"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{FieldDelimiter: ',', Header: true, HeaderSet: tt.headerSet, Sniff: true}
+			db := NewDB(stringConnector{content: tt.content}, &csvquery.Query{}, zap.NewNop(), conf)
+
+			table := NewTable("sales.csv", db.query, db)
+			source, err := table.connect()
+			assert.NoError(t, err)
+
+			header, err := source.readHeader()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHeader, header)
+		})
+	}
+}
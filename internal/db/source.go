@@ -0,0 +1,103 @@
+package db
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+)
+
+// tableExtensions maps extensions of table files to their input formats.
+var tableExtensions = map[string]string{
+	".csv":     catalog.FormatCSV,
+	".tsv":     catalog.FormatTSV,
+	".xlsx":    catalog.FormatXLSX,
+	".parquet": catalog.FormatParquet,
+	".jsonl":   catalog.FormatJSONLines,
+	".ndjson":  catalog.FormatJSONLines,
+}
+
+// rowSource reads rows of a table in one input format.
+type rowSource interface {
+	// readHeader returns the column names, it's called once before reading rows.
+	readHeader() ([]string, error)
+	// read returns the next data row, io.EOF after the last row and *rowParseError for a malformed row.
+	read() ([]string, error)
+}
+
+// rowParseError describes a row which can't be parsed.
+type rowParseError struct {
+	line int
+	err  error
+}
+
+func (e *rowParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.line, e.err)
+}
+
+func (e *rowParseError) Unwrap() error {
+	return e.err
+}
+
+// tableFormat returns the input format declared in the catalog or the format of the file extension, csv by default.
+// A table with a layout in the catalog is fixed-width.
+func tableFormat(name string, options catalog.TableOptions) string {
+	if options.Format != "" {
+		return options.Format
+	}
+
+	if len(options.Layout) > 0 {
+		return catalog.FormatFixedWidth
+	}
+
+	if format, ok := extensionFormat(name); ok {
+		return format
+	}
+
+	return catalog.FormatCSV
+}
+
+// isTableFile returns true if the file extension is one of table formats.
+func isTableFile(name string) bool {
+	_, ok := extensionFormat(name)
+	return ok
+}
+
+// extensionFormat returns the input format of the file extension, compression extensions are skipped.
+func extensionFormat(name string) (string, bool) {
+	format, ok := tableExtensions[strings.ToLower(path.Ext(trimCompressionExtension(name)))]
+	return format, ok
+}
+
+// csvSource reads rows of a csv table.
+type csvSource struct {
+	table  *Table
+	reader *csv.Reader
+	// firstRow is the first record of a table without a header, it's returned by the first read.
+	firstRow []string
+}
+
+func (s *csvSource) readHeader() ([]string, error) {
+	columns, firstRow, err := s.table.readHeader(s.reader)
+	s.firstRow = firstRow
+	return columns, err
+}
+
+func (s *csvSource) read() ([]string, error) {
+	if s.firstRow != nil {
+		row := s.firstRow
+		s.firstRow = nil
+		return row, nil
+	}
+
+	row, err := s.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, &rowParseError{line: parseErr.StartLine, err: parseErr.Err}
+	}
+
+	return row, err
+}
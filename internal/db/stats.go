@@ -0,0 +1,136 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxRejectedLines is the number of reported line numbers of malformed rows per table.
+const maxRejectedLines = 5
+
+// tableStats collects execution statistics of a table, counters are updated concurrently by the table goroutines.
+// Durations are in nanoseconds and are measured only by EXPLAIN ANALYZE.
+type tableStats struct {
+	bytesRead   int64
+	rowsScanned int64
+	rowsMatched int64
+	parseErrors int64
+	// rowGroups and rowGroupsSkipped count row groups of a Parquet table, skipped ones aren't read.
+	rowGroups        int64
+	rowGroupsSkipped int64
+
+	// rejectedLines contains the first line numbers of malformed rows skipped in the lenient parse mode,
+	// it's written only by the goroutine reading the table.
+	rejectedLines []int
+
+	decodeTime int64
+	evalTime   int64
+	headerWait int64
+	resultWait int64
+}
+
+// lines returns the statistics, one value per line.
+func (s *tableStats) lines(indent string) []string {
+	duration := func(ns *int64) string {
+		return time.Duration(atomic.LoadInt64(ns)).Round(time.Microsecond).String()
+	}
+
+	parseErrors := fmt.Sprintf("%sParse errors: %d", indent, atomic.LoadInt64(&s.parseErrors))
+	if len(s.rejectedLines) > 0 {
+		parseErrors += fmt.Sprintf(" (%s)", s.rejectedLinesString())
+	}
+
+	lines := []string{
+		fmt.Sprintf("%sBytes read: %d", indent, atomic.LoadInt64(&s.bytesRead)),
+		fmt.Sprintf("%sRows scanned: %d", indent, atomic.LoadInt64(&s.rowsScanned)),
+		fmt.Sprintf("%sRows matched: %d", indent, atomic.LoadInt64(&s.rowsMatched)),
+		parseErrors,
+	}
+
+	if groups := atomic.LoadInt64(&s.rowGroups); groups > 0 {
+		skipped := atomic.LoadInt64(&s.rowGroupsSkipped)
+		lines = append(lines, fmt.Sprintf("%sRow groups: %d read, %d skipped", indent, groups-skipped, skipped))
+	}
+
+	return append(lines,
+		fmt.Sprintf("%sDecoding: %s", indent, duration(&s.decodeTime)),
+		fmt.Sprintf("%sPredicate evaluation: %s (sum of all workers)", indent, duration(&s.evalTime)),
+		fmt.Sprintf("%sBlocked on headers channel: %s", indent, duration(&s.headerWait)),
+		fmt.Sprintf("%sBlocked on result channel: %s", indent, duration(&s.resultWait)),
+	)
+}
+
+// rejectedLinesString returns the reported line numbers of malformed rows, e.g. "lines 4, 9, ...".
+func (s *tableStats) rejectedLinesString() string {
+	numbers := make([]string, 0, len(s.rejectedLines)+1)
+	for _, line := range s.rejectedLines {
+		numbers = append(numbers, strconv.Itoa(line))
+	}
+	if atomic.LoadInt64(&s.parseErrors) > int64(len(s.rejectedLines)) {
+		numbers = append(numbers, "...")
+	}
+
+	return "lines " + strings.Join(numbers, ", ")
+}
+
+// rejectedWarnings returns warnings about malformed rows skipped in the tables, one warning per table.
+func (db *DB) rejectedWarnings() []string {
+	var warnings []string
+	for _, table := range db.tables {
+		rejected := atomic.LoadInt64(&table.stats.parseErrors)
+		if rejected == 0 {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf("Warning: %d malformed rows skipped in '%s' (%s)",
+			rejected, table.name, table.stats.rejectedLinesString()))
+	}
+
+	return warnings
+}
+
+// countingReader counts bytes read from the table.
+type countingReader struct {
+	io.ReadCloser
+	count *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(r.count, int64(n))
+	return n, err
+}
+
+// countingReaderAt counts bytes read from the table at random offsets.
+type countingReaderAt struct {
+	io.ReaderAt
+	count *int64
+}
+
+func (r *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.ReaderAt.ReadAt(p, off)
+	atomic.AddInt64(r.count, int64(n))
+	return n, err
+}
+
+// startTimer returns the current time if the query is analyzed, zero time otherwise.
+func (db *DB) startTimer() time.Time {
+	if !db.analyze {
+		return time.Time{}
+	}
+
+	return time.Now()
+}
+
+// stopTimer adds the time passed since start to the counter if the query is analyzed.
+func (db *DB) stopTimer(start time.Time, counter *int64) {
+	if !db.analyze {
+		return
+	}
+
+	atomic.AddInt64(counter, int64(time.Since(start)))
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+func TestCountingReader(t *testing.T) {
+	var count int64
+	reader := &countingReader{ReadCloser: io.NopCloser(strings.NewReader("id,name\n1,Bob\n")), count: &count}
+
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), count)
+}
+
+func TestDB_runAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	content := "id,name\n1,Bob\n2,Ann\n3,Tom\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte(content), 0600))
+
+	conf := &config.Config{TableLocation: dir, Workers: 2, FieldDelimiter: ',', Header: true, Ordered: true}
+	query := csvquery.NewQuery("select name from users.csv where id > 1", zap.NewNop())
+	db := NewDB(FileTableConnector{}, query, zap.NewNop(), conf)
+	db.analyze = true
+
+	rowCount, err := db.run(context.Background(), context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rowCount)
+
+	assert.Len(t, db.tables, 1)
+	stats := &db.tables[0].stats
+	assert.Equal(t, int64(len(content)), stats.bytesRead)
+	assert.Equal(t, int64(3), stats.rowsScanned)
+	assert.Equal(t, int64(2), stats.rowsMatched)
+	assert.Equal(t, int64(0), stats.parseErrors)
+	assert.Positive(t, stats.decodeTime)
+	assert.Positive(t, stats.evalTime)
+
+	lines := stats.lines("  ")
+	assert.Equal(t, "  Rows scanned: 3", lines[1])
+	assert.True(t, strings.HasPrefix(lines[len(lines)-4], "  Decoding: "), lines)
+}
@@ -1,17 +1,25 @@
 package db
 
 import (
+	"bufio"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"path"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/config"
 	"github.com/phpCoder88/csv-searcher/internal/csvquery"
-	"github.com/phpCoder88/csv-searcher/internal/structs"
 )
 
 // Table describes table entity.
@@ -20,35 +28,89 @@ type Table struct {
 	query      *csvquery.Query
 	connection io.ReadCloser
 	mapColumns map[csvquery.Column]int
+	options    catalog.TableOptions
+	// format is the input format of the table, one of catalog formats.
+	format string
+	// file is the table file name, sheet is the worksheet of a workbook table.
+	file  string
+	sheet string
+
+	// delimiter, quote and header are the effective format of the table:
+	// catalog options, the detected dialect or the session settings.
+	delimiter rune
+	quote     byte
+	header    bool
+
+	// fileColumn is true if the virtual _file column is used, the file name is appended to every row then.
+	fileColumn bool
+
+	// prev is closed when the previous table of the query is finished, it's nil for the first table.
+	prev <-chan struct{}
+	done chan struct{}
+
+	stats tableStats
 
 	db *DB
 }
 
+// tableRow describes a table row with its position in the table.
+type tableRow struct {
+	seq     int
+	values  []string
+	matched bool
+}
+
 // NewTable returns new instance of Table.
 func NewTable(
 	name csvquery.Table,
 	query *csvquery.Query,
 	db *DB,
 ) *Table {
+	file, sheet := splitSheet(string(name))
+	options := db.catalog.Table(file)
+	format := tableFormat(file, options)
+	if sheet == "" {
+		sheet = options.Sheet
+	}
+
+	// Columns of a fixed-width table are named by the layout, the first line is a header only if the catalog says so.
+	header := options.HasHeader(db.config.Header)
+	if format == catalog.FormatFixedWidth {
+		header = options.HasHeader(false)
+	}
+
 	return &Table{
 		name:       name,
 		query:      query,
 		mapColumns: make(map[csvquery.Column]int, len(query.UsedColumns)),
+		options:    options,
+		format:     format,
+		file:       file,
+		sheet:      sheet,
+		delimiter:  options.DelimiterRune(db.config.FieldDelimiter),
+		quote:      options.QuoteByte(),
+		header:     header,
+		done:       make(chan struct{}),
 		db:         db,
 	}
 }
 
 // Exists checks whether a table exists.
 func (t *Table) Exists() bool {
-	tablePath := path.Join(t.db.config.TableLocation, string(t.name))
-	return t.db.connector.Exists(tablePath)
+	return t.db.connector.Exists(t.path())
+}
+
+// path returns the path of the table file.
+func (t *Table) path() string {
+	return path.Join(t.db.config.TableLocation, t.file)
 }
 
 func (t *Table) executeOnTable(ctx context.Context) {
-	reader, err := t.connect()
+	defer close(t.done)
+
+	source, err := t.connect()
 	if err != nil {
-		t.db.logger.Error(err.Error())
-		t.db.errorCh <- err
+		t.sendError(ctx, err)
 		return
 	}
 
@@ -59,41 +121,170 @@ func (t *Table) executeOnTable(ctx context.Context) {
 		}
 	}()
 
-	tableColumnNames, err := reader.Read()
+	tableColumnNames, err := source.readHeader()
 	if err != nil {
 		userErr := fmt.Errorf("%w: '%s'", ErrTableColumnsRead, t.name)
 		t.db.logger.Error(fmt.Errorf("%w, Real Error: %v", userErr, err).Error())
-		t.db.errorCh <- userErr
+		t.sendError(ctx, userErr)
 		return
 	}
 
 	err = t.checkColumns(tableColumnNames)
 	if err != nil {
-		t.db.logger.Error(err.Error())
-		t.db.errorCh <- err
+		t.sendError(ctx, err)
 		return
 	}
 
+	if t.fileColumn {
+		tableColumnNames = append(tableColumnNames, string(fileColumn))
+	}
+
+	headerStart := t.db.startTimer()
 	t.db.headersCh <- t.chooseColumns(&tableColumnNames)
+	t.db.stopTimer(headerStart, &t.stats.headerWait)
 
-	t.getRows(ctx, reader)
+	t.getRows(ctx, source)
 }
 
-func (t *Table) connect() (*csv.Reader, error) {
-	tablePath := path.Join(t.db.config.TableLocation, string(t.name))
-	file, err := t.db.connector.GetReader(tablePath)
+// hasHeader returns true if the first record of the table is the header.
+func (t *Table) hasHeader() bool {
+	return t.header
+}
+
+// normalizeRow applies the table format options to the decoded data row.
+func (t *Table) normalizeRow(row []string) {
+	t.swapQuotes(row)
+
+	if len(t.options.Null) == 0 {
+		return
+	}
+
+	for i, value := range row {
+		if t.options.IsNull(value) {
+			row[i] = ""
+		}
+	}
+}
+
+// swapQuotes restores quote characters of the fields decoded with the swapped quote.
+func (t *Table) swapQuotes(fields []string) {
+	if t.quote != '"' {
+		swapQuotes(fields, t.quote)
+	}
+}
+
+// readHeader returns the table column names.
+// Names c1, c2, ... are given to columns of a table without a header, its first record is returned as a data row.
+func (t *Table) readHeader(reader *csv.Reader) (columns, firstRow []string, err error) {
+	record, err := reader.Read()
 	if err != nil {
-		// TODO оригинальная ошибка не используется
-		err := fmt.Errorf("%w: %s", ErrTableConnection, t.name)
-		return nil, err
+		return nil, nil, err
 	}
 
-	t.connection = file
+	if t.hasHeader() {
+		t.swapQuotes(record)
+		return record, nil, nil
+	}
 
-	reader := csv.NewReader(file)
-	reader.Comma = t.db.config.FieldDelimiter
+	return synthesizeColumns(len(record)), record, nil
+}
+
+// synthesizeColumns returns names c1, c2, ... for a table without a header.
+func synthesizeColumns(count int) []string {
+	columns := make([]string, count)
+	for i := range columns {
+		columns[i] = "c" + strconv.Itoa(i+1)
+	}
 
-	return reader, nil
+	return columns
+}
+
+// positionalColumn returns the index of the column given as $1, $2, ... or -1.
+func positionalColumn(name csvquery.Column, count int) int {
+	if !strings.HasPrefix(string(name), "$") {
+		return -1
+	}
+
+	pos, err := strconv.Atoi(string(name[1:]))
+	if err != nil || pos < 1 || pos > count {
+		return -1
+	}
+
+	return pos - 1
+}
+
+func (t *Table) connect() (rowSource, error) {
+	err := t.resolveLayout()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrTableConnection, t.name, err)
+	}
+
+	file, err := t.db.connector.GetReader(t.path())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrTableConnection, t.name, err)
+	}
+
+	t.connection = &countingReader{ReadCloser: file, count: &t.stats.bytesRead}
+	switch t.format {
+	case catalog.FormatXLSX:
+		return t.openWorkbook()
+	case catalog.FormatParquet:
+		return t.openParquet(file)
+	}
+
+	buffered := bufio.NewReaderSize(t.connection, sniffSize)
+	sample, err := buffered.Peek(sniffSize)
+	enc, err := t.tableEncoding(sample, err != nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrTableConnection, t.name, err)
+	}
+	if enc != unicode.UTF8 {
+		buffered = bufio.NewReaderSize(transform.NewReader(buffered, enc.NewDecoder()), sniffSize)
+	}
+
+	skipBOM(buffered)
+	switch t.format {
+	case catalog.FormatJSONLines:
+		return newJSONSource(buffered), nil
+	case catalog.FormatTSV:
+		delimiter := t.options.DelimiterRune('\t')
+		return &tsvSource{table: t, lines: lineReader{reader: buffered}, delimiter: string(delimiter)}, nil
+	case catalog.FormatFixedWidth:
+		return &fixedSource{table: t, lines: lineReader{reader: buffered}, layout: t.options.Layout}, nil
+	}
+
+	if t.db.config.Sniff {
+		sample, err := buffered.Peek(sniffSize)
+		t.applyDialect(sniffDialect(sample, err != nil))
+	}
+
+	var source io.Reader = buffered
+	if t.quote != '"' {
+		source = &quoteSwapReader{reader: source, quote: t.quote}
+	}
+
+	reader := csv.NewReader(source)
+	reader.Comma = t.delimiter
+	reader.Comment = t.options.CommentRune()
+
+	return &csvSource{table: t, reader: reader}, nil
+}
+
+// applyDialect uses the detected format for options which aren't set in the catalog
+// or explicitly by the session DELIMITER and HEADER.
+// A header is assumed unless the session disables it or the first line looks like a data row.
+func (t *Table) applyDialect(d dialect) {
+	if t.options.Delimiter == "" && !t.db.config.DelimiterSet && d.delimiter != 0 {
+		t.delimiter = d.delimiter
+	}
+
+	if t.options.Quote == "" && d.quote != 0 {
+		t.quote = d.quote
+	}
+
+	if t.options.Header == nil && !t.db.config.HeaderSet && d.noHeader {
+		t.header = false
+	}
 }
 
 func (t *Table) checkColumns(tableColumns []string) error {
@@ -112,6 +303,27 @@ func (t *Table) checkColumns(tableColumns []string) error {
 		}
 	}
 
+	if !t.hasHeader() {
+		unknown := queryColumnNames[:0]
+		for _, queryColName := range queryColumnNames {
+			if pos := positionalColumn(queryColName, len(tableColumns)); pos != -1 {
+				t.mapColumns[queryColName] = pos
+				continue
+			}
+			unknown = append(unknown, queryColName)
+		}
+		queryColumnNames = unknown
+	}
+
+	for j, queryColName := range queryColumnNames {
+		if queryColName == fileColumn {
+			t.mapColumns[fileColumn] = len(tableColumns)
+			t.fileColumn = true
+			queryColumnNames = append(queryColumnNames[:j], queryColumnNames[j+1:]...)
+			break
+		}
+	}
+
 	if len(queryColumnNames) > 0 {
 		return fmt.Errorf("%w: table: '%s', columns: %v", ErrNotExistColumn, t.name, queryColumnNames)
 	}
@@ -119,60 +331,169 @@ func (t *Table) checkColumns(tableColumns []string) error {
 	return nil
 }
 
-func (t *Table) getRows(ctx context.Context, reader *csv.Reader) {
-	workerInput := make(chan []string, t.db.config.Workers)
+// getRows processes all rows of the source.
+func (t *Table) getRows(ctx context.Context, source rowSource) {
+	workerInput := make(chan tableRow, t.db.config.Workers)
+	checkedRows := make(chan tableRow, t.db.config.Workers)
 
 	var wg sync.WaitGroup
 	wg.Add(t.db.config.Workers)
 	for i := 0; i < t.db.config.Workers; i++ {
 		go func() {
-			t.processRow(workerInput)
+			t.processRow(ctx, workerInput, checkedRows)
 			wg.Done()
 		}()
 	}
 
+	collected := make(chan struct{})
+	go func() {
+		t.collectRows(ctx, checkedRows)
+		close(collected)
+	}()
+
+	var seq int
 reader:
-	for atomic.LoadInt32(&t.db.selected) < t.db.config.Limit {
-		row, err := reader.Read()
+	for !t.db.limitReached() {
+		decodeStart := t.db.startTimer()
+		row, err := source.read()
+		t.db.stopTimer(decodeStart, &t.stats.decodeTime)
 		if err != nil {
-			if err == io.EOF {
+			if err == io.EOF || !t.rejectRow(ctx, err) {
 				break
 			}
-
-			t.db.logger.Error(err.Error())
-			break
+			continue
+		}
+		atomic.AddInt64(&t.stats.rowsScanned, 1)
+		t.normalizeRow(row)
+		if t.fileColumn {
+			row = append(row, string(t.name))
 		}
 
 		select {
 		case <-ctx.Done():
 			break reader
-		case workerInput <- row:
-			break
+		case workerInput <- tableRow{seq: seq, values: row}:
+			seq++
 		}
 	}
 
 	close(workerInput)
 	wg.Wait()
+	close(checkedRows)
+	<-collected
 }
 
-func (t *Table) processRow(in <-chan []string) {
+// rejectRow handles the row which can't be read and returns false if reading of the table must stop.
+// A malformed row fails the query in the strict parse mode and is skipped in the lenient one.
+func (t *Table) rejectRow(ctx context.Context, err error) bool {
+	var parseErr *rowParseError
+	if !errors.As(err, &parseErr) {
+		t.sendError(ctx, fmt.Errorf("%w: '%s': %v", ErrTableRead, t.name, err))
+		return false
+	}
+
+	atomic.AddInt64(&t.stats.parseErrors, 1)
+	if t.db.config.ParseMode != config.ParseModeLenient {
+		t.sendError(ctx, fmt.Errorf("%w: table '%s', line %d: %v", ErrMalformedRow, t.name, parseErr.line, parseErr.err))
+		return false
+	}
+
+	t.db.logger.Warn(fmt.Sprintf("Skipping malformed row of table '%s': %v", t.name, err))
+	if len(t.stats.rejectedLines) < maxRejectedLines {
+		t.stats.rejectedLines = append(t.stats.rejectedLines, parseErr.line)
+	}
+
+	return true
+}
+
+// sendError sends the error to the executor unless the query is already finished.
+func (t *Table) sendError(ctx context.Context, err error) {
+	t.db.logger.Error(err.Error())
+	select {
+	case <-ctx.Done():
+	case t.db.errorCh <- err:
+	}
+}
+
+func (t *Table) processRow(ctx context.Context, in <-chan tableRow, out chan<- tableRow) {
 	for input := range in {
-		rowOk, err := t.checkRow(&input)
+		evalStart := t.db.startTimer()
+		rowOk, err := t.checkRow(&input.values)
+		t.db.stopTimer(evalStart, &t.stats.evalTime)
 		if err != nil {
-			t.db.errorCh <- err
+			t.sendError(ctx, err)
 			return
 		}
 
+		checked := tableRow{seq: input.seq, matched: rowOk}
 		if rowOk {
-			if atomic.LoadInt32(&t.db.selected) < t.db.config.Limit {
-				atomic.AddInt32(&t.db.selected, 1)
-				t.db.resultCh <- t.chooseColumns(&input)
-			}
+			atomic.AddInt64(&t.stats.rowsMatched, 1)
+			checked.values = t.chooseColumns(&input.values)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case out <- checked:
 		}
 		runtime.Gosched()
 	}
 }
 
+// collectRows sends matched rows to the result.
+// In the ordered mode rows are sent in the file order after all rows of the previous table.
+func (t *Table) collectRows(ctx context.Context, in <-chan tableRow) {
+	if !t.db.config.Ordered {
+		for row := range in {
+			if row.matched && !t.sendRow(ctx, row.values) {
+				return
+			}
+		}
+		return
+	}
+
+	if t.prev != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.prev:
+		}
+	}
+
+	pending := make(map[int]tableRow)
+	var next int
+	for row := range in {
+		pending[row.seq] = row
+
+		for nextRow, ok := pending[next]; ok; nextRow, ok = pending[next] {
+			delete(pending, next)
+			next++
+
+			if nextRow.matched && !t.sendRow(ctx, nextRow.values) {
+				return
+			}
+		}
+	}
+}
+
+// sendRow sends the row to the result if the limit isn't reached.
+// It returns false if the query is canceled.
+func (t *Table) sendRow(ctx context.Context, values []string) bool {
+	if !t.db.reserveRow() {
+		return true
+	}
+
+	start := t.db.startTimer()
+	defer t.db.stopTimer(start, &t.stats.resultWait)
+
+	select {
+	case <-ctx.Done():
+		return false
+	case t.db.resultCh <- values:
+		return true
+	}
+}
+
 func (t *Table) checkRow(columns *[]string) (bool, error) {
 	if t.query.Where == nil {
 		return true, nil
@@ -186,7 +507,11 @@ func (t *Table) chooseColumns(input *[]string) []string {
 
 	for _, col := range t.query.Select {
 		if col == "*" {
-			filteredColumns = append(filteredColumns, *input...)
+			columns := *input
+			if t.fileColumn {
+				columns = columns[:len(columns)-1]
+			}
+			filteredColumns = append(filteredColumns, columns...)
 			continue
 		}
 
@@ -197,50 +522,34 @@ func (t *Table) chooseColumns(input *[]string) []string {
 	return filteredColumns
 }
 
-func (t *Table) calcConditions(node *structs.Tree, cols *[]string) (bool, error) {
-	var leftRes bool
-	var rightRes bool
-	var err error
-
-	if node.LeftChild() != nil {
-		leftRes, err = t.calcConditions(node.LeftChild(), cols)
+func (t *Table) calcConditions(node csvquery.Expr, cols *[]string) (bool, error) {
+	switch expr := node.(type) {
+	case *csvquery.BinaryExpr:
+		leftRes, err := t.calcConditions(expr.Left, cols)
 		if err != nil {
 			return false, err
 		}
-	}
 
-	op, ok := node.GetValue().(string)
-	if ok && csvquery.IsOperator(op) {
-		// Operator
-		if leftRes && csvquery.IsSameOperator(op, csvquery.OrOperator) {
+		if leftRes && expr.Op == csvquery.OrOperator {
 			return true, nil
-		} else if !leftRes && csvquery.IsSameOperator(op, csvquery.AndOperator) {
+		} else if !leftRes && expr.Op == csvquery.AndOperator {
 			return false, nil
 		}
 
-		rightRes, err = t.calcConditions(node.RightChild(), cols)
+		rightRes, err := t.calcConditions(expr.Right, cols)
 		if err != nil {
 			return false, err
 		}
 
-		return csvquery.Calc(leftRes, rightRes, csvquery.LogicalOperator(op)), nil
-	}
+		return csvquery.Calc(leftRes, rightRes, expr.Op), nil
 
-	if node.RightChild() != nil {
-		err = fmt.Errorf("%w: get not empty right tree branch", ErrIncorrectWhereTree)
-		t.db.logger.Error(err.Error())
-		return false, ErrIncorrectWhereTree
+	case *csvquery.Condition:
+		return t.calcCondition(expr, cols)
 	}
 
-	// Condition
-	cond, ok := node.GetValue().(*csvquery.Condition)
-	if !ok {
-		err := fmt.Errorf("%w: cant cast cond %v to type *Condition", ErrIncorrectWhereTree, node.GetValue())
-		t.db.logger.Error(err.Error())
-		return false, ErrIncorrectWhereTree
-	}
-
-	return t.calcCondition(cond, cols)
+	err := fmt.Errorf("%w: unknown expression %v", ErrIncorrectWhereTree, node)
+	t.db.logger.Error(err.Error())
+	return false, ErrIncorrectWhereTree
 }
 
 func (t *Table) calcCondition(cond *csvquery.Condition, cols *[]string) (bool, error) {
@@ -253,6 +562,14 @@ func (t *Table) calcCondition(cond *csvquery.Condition, cols *[]string) (bool, e
 	}
 	colValue := (*cols)[fieldInd]
 
+	if t.options.ColumnType(string(cond.Column)) == catalog.TypeDate {
+		result, err := t.checkDateCondition(cond, colValue)
+		if err != nil {
+			t.db.logger.Error(err.Error())
+		}
+		return result, err
+	}
+
 	result, err := cond.CheckCondition(colValue)
 	if err != nil {
 		t.db.logger.Error(err.Error())
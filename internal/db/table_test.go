@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+func TestTable_collectRows(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int32
+		wantResult [][]string
+	}{
+		{
+			name:       "all rows",
+			wantResult: [][]string{{"0"}, {"2"}, {"3"}, {"5"}},
+		},
+		{
+			name:       "limited rows",
+			limit:      2,
+			wantResult: [][]string{{"0"}, {"2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &DB{
+				config:   &config.Config{Ordered: true, Limit: tt.limit},
+				resultCh: make(chan []string, 10),
+			}
+			table := &Table{db: db}
+
+			checkedRows := make(chan tableRow, 10)
+			for _, seq := range []int{3, 1, 0, 5, 4, 2} {
+				row := tableRow{seq: seq, matched: seq != 1 && seq != 4}
+				if row.matched {
+					row.values = []string{string(rune('0' + seq))}
+				}
+				checkedRows <- row
+			}
+			close(checkedRows)
+
+			table.collectRows(context.Background(), checkedRows)
+			close(db.resultCh)
+
+			var result [][]string
+			for row := range db.resultCh {
+				result = append(result, row)
+			}
+			assert.Equal(t, tt.wantResult, result)
+		})
+	}
+}
+
+func TestTable_withoutHeader(t *testing.T) {
+	query := csvquery.NewQuery("select c2, $1 from logs.csv where $3 > 1", zap.NewNop())
+	assert.NoError(t, query.Parse())
+
+	db := &DB{config: &config.Config{Header: false}, logger: zap.NewNop()}
+	table := NewTable("logs.csv", query, db)
+
+	reader := csv.NewReader(strings.NewReader("a,b,1\nc,d,2\n"))
+	columns, firstRow, err := table.readHeader(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c1", "c2", "c3"}, columns)
+	assert.Equal(t, []string{"a", "b", "1"}, firstRow)
+
+	assert.NoError(t, table.checkColumns(columns))
+	assert.Equal(t, []string{"b", "a"}, table.chooseColumns(&firstRow))
+
+	matched, err := table.checkRow(&firstRow)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+
+	db.config.Header = true
+	table = NewTable("logs.csv", query, db)
+	columns, firstRow, err = table.readHeader(csv.NewReader(strings.NewReader("a,b,1\n")))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "1"}, columns)
+	assert.Nil(t, firstRow)
+	assert.ErrorIs(t, table.checkColumns([]string{"c1", "c2", "c3"}), ErrNotExistColumn)
+}
+
+func TestExecute_parseMode(t *testing.T) {
+	dir := t.TempDir()
+	tableContent := "id,name\n1,a\n2,b,extra\n3,c\"d\n4,e\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte(tableContent), 0600))
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		ParseMode:      config.ParseModeStrict,
+		FieldDelimiter: ',',
+	}
+
+	err := Execute(context.Background(), FileTableConnector{}, "select * from users.csv into outfile 'strict.csv'", conf, zap.NewNop())
+	assert.ErrorIs(t, err, ErrMalformedRow)
+	assert.Contains(t, err.Error(), "line 3")
+	assert.NoFileExists(t, filepath.Join(dir, "strict.csv"))
+
+	conf.ParseMode = config.ParseModeLenient
+	query := csvquery.NewQuery("select * from users.csv into outfile 'lenient.csv'", zap.NewNop())
+	db := NewDB(FileTableConnector{}, query, zap.NewNop(), conf)
+	rowCount, err := db.run(context.Background(), context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rowCount)
+
+	content, err := os.ReadFile(filepath.Join(dir, "lenient.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,name\n1,a\n4,e\n", string(content))
+	assert.Equal(t, []string{"Warning: 2 malformed rows skipped in 'users.csv' (lines 3, 4)"}, db.rejectedWarnings())
+}
+
+func TestTableStats_rejectedLinesString(t *testing.T) {
+	stats := tableStats{parseErrors: 7, rejectedLines: []int{2, 5, 6, 10, 11}}
+	assert.Equal(t, "lines 2, 5, 6, 10, 11, ...", stats.rejectedLinesString())
+
+	stats = tableStats{parseErrors: 1, rejectedLines: []int{2}}
+	assert.Equal(t, "lines 2", stats.rejectedLinesString())
+}
+
+func TestTable_executeOnTableCanceled(t *testing.T) {
+	query := csvquery.NewQuery("select age from users.csv", zap.NewNop())
+	assert.NoError(t, query.Parse())
+
+	conf := &config.Config{FieldDelimiter: ',', Header: true, Workers: 1}
+	db := NewDB(stringConnector{content: "id,name\n1,Bob\n"}, query, zap.NewNop(), conf)
+	table := NewTable("users.csv", query, db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// nobody receives the error of the unknown column after cancellation
+	go table.executeOnTable(ctx)
+	select {
+	case <-table.done:
+	case <-time.After(time.Second):
+		t.Fatal("the table is blocked on sending the error")
+	}
+}
+
+func TestExecute_failedQueryLeavesNoGoroutines(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{
+		"a.csv": "name,age\nBob,30\nAnn,25\n",
+		"b.csv": "name\nCarol\n",
+	})
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+	}
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		err := Execute(context.Background(), FileTableConnector{}, "select name from a.csv, b.csv where name > 5 into outfile 'result.csv'", conf, zap.NewNop())
+		assert.Error(t, err)
+
+		err = Execute(context.Background(), FileTableConnector{}, "select * from a.csv, b.csv into outfile 'result.csv'", conf, zap.NewNop())
+		assert.ErrorIs(t, err, ErrIncorrectColumnCount)
+	}
+
+	// the last goroutines may still be returning after the result is sent
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
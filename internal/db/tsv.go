@@ -0,0 +1,100 @@
+package db
+
+import (
+	"encoding/csv"
+	"strings"
+)
+
+// tsvSource reads rows of a TSV table. Fields aren't quoted, tabs, line breaks and backslashes
+// in values are escaped as \t, \n, \r and \\.
+type tsvSource struct {
+	table     *Table
+	lines     lineReader
+	delimiter string
+	fields    int
+	// firstRow is the first record of a table without a header, it's returned by the first read.
+	firstRow []string
+}
+
+func (s *tsvSource) readHeader() ([]string, error) {
+	record, err := s.record()
+	if err != nil {
+		return nil, err
+	}
+	s.fields = len(record)
+
+	if s.table.hasHeader() {
+		return record, nil
+	}
+
+	s.firstRow = record
+	return synthesizeColumns(len(record)), nil
+}
+
+func (s *tsvSource) read() ([]string, error) {
+	if s.firstRow != nil {
+		row := s.firstRow
+		s.firstRow = nil
+		return row, nil
+	}
+
+	row, err := s.record()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(row) != s.fields {
+		return nil, &rowParseError{line: s.lines.line, err: csv.ErrFieldCount}
+	}
+
+	return row, nil
+}
+
+// record returns unescaped fields of the next record.
+func (s *tsvSource) record() ([]string, error) {
+	line, err := s.lines.nextRecord(s.table.options.CommentRune())
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Split(line, s.delimiter)
+	for i, field := range fields {
+		fields[i] = unescapeTSV(field)
+	}
+
+	return fields, nil
+}
+
+// unescapeTSV replaces escape sequences of the field, unknown sequences like \N are kept as is.
+func unescapeTSV(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var b strings.Builder
+	b.Grow(len(field))
+
+	for i := 0; i < len(field); i++ {
+		if field[i] != '\\' || i == len(field)-1 {
+			b.WriteByte(field[i])
+			continue
+		}
+
+		i++
+		switch field[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '\\':
+			b.WriteByte('\\')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(field[i])
+		}
+	}
+
+	return b.String()
+}
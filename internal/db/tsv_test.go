@@ -0,0 +1,89 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/config"
+)
+
+func TestUnescapeTSV(t *testing.T) {
+	tests := map[string]string{
+		`plain`:         "plain",
+		`a\tb`:          "a\tb",
+		`line\nbreak\r`: "line\nbreak\r",
+		`C:\\temp`:      `C:\temp`,
+		`\N`:            `\N`,
+		`"quoted"`:      `"quoted"`,
+		`trailing\`:     `trailing\`,
+		`\\\\n`:         `\\n`,
+	}
+
+	for field, want := range tests {
+		assert.Equal(t, want, unescapeTSV(field), field)
+	}
+}
+
+func TestTSVSource(t *testing.T) {
+	table := &Table{header: true}
+	source := &tsvSource{
+		table:     table,
+		lines:     lineReader{reader: bufio.NewReader(strings.NewReader("id\tnote\r\n1\t\"a\\tb\"\n\n2\n3\tc\\nd\n"))},
+		delimiter: "\t",
+	}
+
+	header, err := source.readHeader()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "note"}, header)
+
+	row, err := source.read()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "\"a\tb\""}, row)
+
+	_, err = source.read()
+	var parseErr *rowParseError
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, 4, parseErr.line)
+	assert.ErrorIs(t, err, csv.ErrFieldCount)
+
+	row, err = source.read()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3", "c\nd"}, row)
+
+	_, err = source.read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestExecute_tsv(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{
+		"notes.tsv": "id\tnote\n1\t\"quoted\" text\n2\tC:\\\\temp\\tdir\n",
+	})
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+	}
+
+	err := Execute(context.Background(), FileTableConnector{}, "select note from notes.tsv where id > 0 into outfile 'notes.csv'", conf, zap.NewNop())
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "notes.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "note\n\"\"\"quoted\"\" text\"\nC:\\temp\tdir\n", string(content))
+}
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+// sheetSeparator separates the workbook file and the sheet in table names like report.xlsx!Sheet1.
+const sheetSeparator = "!"
+
+// errNoSheets indicates that the workbook has no worksheets.
+var errNoSheets = errors.New("workbook has no sheets")
+
+// splitSheet splits a workbook table name into the file and the sheet names at the first separator
+// after the workbook extension, sheet names may contain the separator too.
+// Names of other tables and workbooks without a sheet are returned as the file name.
+func splitSheet(name string) (file, sheet string) {
+	for i := strings.Index(name, sheetSeparator); i != -1; {
+		if format, ok := extensionFormat(name[:i]); ok && format == catalog.FormatXLSX {
+			return name[:i], name[i+len(sheetSeparator):]
+		}
+
+		next := strings.Index(name[i+1:], sheetSeparator)
+		if next == -1 {
+			break
+		}
+		i += next + 1
+	}
+
+	return name, ""
+}
+
+// withSheet appends the sheet to names of the workbook tables.
+func withSheet(tables csvquery.Tables, sheet string) csvquery.Tables {
+	if sheet == "" {
+		return tables
+	}
+
+	for i, table := range tables {
+		tables[i] = table + csvquery.Table(sheetSeparator+sheet)
+	}
+
+	return tables
+}
+
+// workbookConnection closes the sheet rows and the workbook together with the table file.
+type workbookConnection struct {
+	io.ReadCloser
+	workbook *excelize.File
+	rows     *excelize.Rows
+}
+
+func (c *workbookConnection) Close() error {
+	_ = c.rows.Close()
+	_ = c.workbook.Close()
+
+	return c.ReadCloser.Close()
+}
+
+// openWorkbook reads the workbook and opens rows of the table sheet, the first sheet by default.
+func (t *Table) openWorkbook() (rowSource, error) {
+	workbook, err := excelize.OpenReader(t.connection)
+	if err != nil {
+		_ = t.connection.Close()
+		return nil, fmt.Errorf("%w: %s: %v", ErrTableConnection, t.name, err)
+	}
+
+	sheet := t.sheet
+	if sheet == "" {
+		sheets := workbook.GetSheetList()
+		if len(sheets) == 0 {
+			_ = workbook.Close()
+			_ = t.connection.Close()
+			return nil, fmt.Errorf("%w: %s: %v", ErrTableConnection, t.name, errNoSheets)
+		}
+		sheet = sheets[0]
+	}
+
+	rows, err := workbook.Rows(sheet)
+	if err != nil {
+		_ = workbook.Close()
+		_ = t.connection.Close()
+		return nil, fmt.Errorf("%w: %s: %v", ErrTableConnection, t.name, err)
+	}
+
+	t.connection = &workbookConnection{ReadCloser: t.connection, workbook: workbook, rows: rows}
+
+	return &xlsxSource{table: t, rows: rows}, nil
+}
+
+// xlsxSource reads rows of an Excel worksheet, cell values are raw without number formats,
+// so formatted numbers are compared as numbers.
+// Empty rows are skipped, missing cells at the end of a row are empty values.
+type xlsxSource struct {
+	table *Table
+	rows  *excelize.Rows
+	// row is the number of the current worksheet row.
+	row    int
+	fields int
+	// firstRow is the first record of a table without a header, it's returned by the first read.
+	firstRow []string
+}
+
+func (s *xlsxSource) readHeader() ([]string, error) {
+	record, err := s.record()
+	if err != nil {
+		return nil, err
+	}
+	s.fields = len(record)
+
+	if s.table.hasHeader() {
+		return record, nil
+	}
+
+	s.firstRow = record
+	return synthesizeColumns(len(record)), nil
+}
+
+func (s *xlsxSource) read() ([]string, error) {
+	if s.firstRow != nil {
+		row := s.firstRow
+		s.firstRow = nil
+		return row, nil
+	}
+
+	record, err := s.record()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(record) > s.fields && !isEmptyRow(record[s.fields:]) {
+		return nil, &rowParseError{line: s.row, err: csv.ErrFieldCount}
+	}
+
+	row := make([]string, s.fields)
+	copy(row, record)
+
+	return row, nil
+}
+
+// record returns cells of the next non-empty row.
+func (s *xlsxSource) record() ([]string, error) {
+	for s.rows.Next() {
+		s.row++
+
+		cells, err := s.rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, &rowParseError{line: s.row, err: err}
+		}
+
+		if !isEmptyRow(cells) {
+			return cells, nil
+		}
+	}
+
+	if err := s.rows.Error(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// isEmptyRow returns true if all cells are empty.
+func isEmptyRow(cells []string) bool {
+	for _, cell := range cells {
+		if cell != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,173 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+
+	"github.com/phpCoder88/csv-searcher/internal/catalog"
+	"github.com/phpCoder88/csv-searcher/internal/config"
+	"github.com/phpCoder88/csv-searcher/internal/csvquery"
+)
+
+// createWorkbook returns an xlsx workbook with the sheets in the given order, every sheet is a list of rows.
+func createWorkbook(t *testing.T, names []string, sheets map[string][][]interface{}) string {
+	workbook := excelize.NewFile()
+	defer workbook.Close()
+
+	for i, name := range names {
+		if i == 0 {
+			assert.NoError(t, workbook.SetSheetName("Sheet1", name))
+		} else {
+			_, err := workbook.NewSheet(name)
+			assert.NoError(t, err)
+		}
+
+		for j, row := range sheets[name] {
+			cell, err := excelize.CoordinatesToCellName(1, j+1)
+			assert.NoError(t, err)
+			assert.NoError(t, workbook.SetSheetRow(name, cell, &row))
+		}
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, workbook.Write(&buf))
+
+	return buf.String()
+}
+
+func TestSplitSheet(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		sheet string
+	}{
+		{name: "report.xlsx!Sheet1", file: "report.xlsx", sheet: "Sheet1"},
+		{name: "reports/q1.XLSX!Q1!Totals", file: "reports/q1.XLSX", sheet: "Q1!Totals"},
+		{name: "news!/q1.xlsx!Totals", file: "news!/q1.xlsx", sheet: "Totals"},
+		{name: "report.xlsx", file: "report.xlsx"},
+		{name: "news!.csv", file: "news!.csv"},
+		{name: "users.csv!Sheet1", file: "users.csv!Sheet1"},
+	}
+
+	for _, tt := range tests {
+		file, sheet := splitSheet(tt.name)
+		assert.Equal(t, tt.file, file, tt.name)
+		assert.Equal(t, tt.sheet, sheet, tt.name)
+	}
+}
+
+func TestExecute_xlsx(t *testing.T) {
+	dir := t.TempDir()
+	report := createWorkbook(t, []string{"Orders", "Totals"}, map[string][][]interface{}{
+		"Orders": {
+			{"id", "customer", "amount"},
+			{1, "Alice", 150.5},
+			{},
+			{2, "Bob"},
+			{3, "Carol", 20, "note"},
+		},
+		"Totals": {
+			{"region", "total"},
+			{"north", 1200},
+			{"south", 300},
+		},
+	})
+	createTables(t, dir, map[string]string{
+		"report.xlsx":        report,
+		"archive/2020.xlsx":  report,
+		"archive/totals.bin": report,
+		catalog.FileName:     "tables:\n  archive/totals.bin:\n    format: xlsx\n    sheet: Totals\n",
+	})
+
+	conf := &config.Config{
+		Timeout:        time.Minute,
+		Workers:        2,
+		TableLocation:  dir,
+		Format:         "csv",
+		Header:         true,
+		Ordered:        true,
+		FieldDelimiter: ',',
+		ParseMode:      config.ParseModeLenient,
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			query: "select customer, amount from report.xlsx where amount > 100 or amount = ''",
+			want:  "customer,amount\nAlice,150.5\nBob,\n",
+		},
+		{
+			query: "select region, _file from 'report.xlsx'!Totals, 'archive/*.xlsx'!totals where total > 500",
+			want:  "region,_file\nnorth,report.xlsx!Totals\nnorth,archive/2020.xlsx!totals\n",
+		},
+		{
+			query: "select region from archive/totals.bin where total < 500",
+			want:  "region\nsouth\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			err := Execute(context.Background(), FileTableConnector{}, tt.query+" into outfile 'result.csv'", conf, zap.NewNop())
+			assert.NoError(t, err)
+
+			content, err := os.ReadFile(filepath.Join(dir, "result.csv"))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(content))
+			assert.NoError(t, os.Remove(filepath.Join(dir, "result.csv")))
+		})
+	}
+
+	err := Execute(context.Background(), FileTableConnector{}, "select region from report.xlsx!Missing", conf, zap.NewNop())
+	assert.ErrorIs(t, err, ErrTableConnection)
+
+	// number formats aren't applied to values
+	workbook := excelize.NewFile()
+	assert.NoError(t, workbook.SetSheetRow("Sheet1", "A1", &[]interface{}{"id", "amount"}))
+	assert.NoError(t, workbook.SetSheetRow("Sheet1", "A2", &[]interface{}{1, 1234.5}))
+	assert.NoError(t, workbook.SetSheetRow("Sheet1", "A3", &[]interface{}{2, 99.999}))
+	format := "#,##0.00"
+	style, err := workbook.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	assert.NoError(t, err)
+	assert.NoError(t, workbook.SetCellStyle("Sheet1", "B2", "B3", style))
+	formatted, err := workbook.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1,234.50", formatted)
+	assert.NoError(t, workbook.SaveAs(filepath.Join(dir, "formatted.xlsx")))
+	assert.NoError(t, workbook.Close())
+
+	err = Execute(context.Background(), FileTableConnector{}, "select id, amount from formatted.xlsx where amount > 100 into outfile 'result.csv'", conf, zap.NewNop())
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, "result.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,amount\n1,1234.5\n", string(content))
+
+	conf.ParseMode = config.ParseModeStrict
+	err = Execute(context.Background(), FileTableConnector{}, "select id from report.xlsx", conf, zap.NewNop())
+	assert.ErrorIs(t, err, ErrMalformedRow)
+}
+
+func TestDB_resolveTables_xlsx(t *testing.T) {
+	dir := t.TempDir()
+	createTables(t, dir, map[string]string{
+		"reports/q1.xlsx": "",
+		"reports/q2.xlsx": "",
+	})
+
+	db := NewDB(FileTableConnector{}, &csvquery.Query{From: csvquery.Tables{"reports/q*.xlsx!Totals", "reports/q1.xlsx!Orders"}},
+		zap.NewNop(), &config.Config{TableLocation: dir})
+
+	tables, err := db.resolveTables(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, csvquery.Tables{"reports/q1.xlsx!Totals", "reports/q2.xlsx!Totals", "reports/q1.xlsx!Orders"}, tables)
+}
@@ -0,0 +1,149 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// QuoteMode describes when delimited fields are enclosed in double quotes.
+type QuoteMode string
+
+const (
+	// QuoteMinimal quotes only fields containing special characters.
+	QuoteMinimal QuoteMode = "minimal"
+	// QuoteAll quotes every field.
+	QuoteAll QuoteMode = "all"
+	// QuoteNone never quotes fields.
+	QuoteNone QuoteMode = "none"
+)
+
+// ErrUnknownQuoteMode indicates that quote mode isn't supported.
+var ErrUnknownQuoteMode = errors.New("unknown quote mode")
+
+// ParseQuoteMode returns the quote mode by its case insensitive name.
+func ParseQuoteMode(name string) (QuoteMode, error) {
+	for _, mode := range []QuoteMode{QuoteMinimal, QuoteAll, QuoteNone} {
+		if strings.EqualFold(name, string(mode)) {
+			return mode, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: '%s'", ErrUnknownQuoteMode, name)
+}
+
+// csvWriter writes delimiter separated values.
+type csvWriter struct {
+	w         io.Writer
+	delimiter rune
+	quote     QuoteMode
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{
+		w:         w,
+		delimiter: ',',
+		quote:     QuoteMinimal,
+	}
+}
+
+// NewDelimitedWriter returns CSV writer with the given field delimiter and quote mode.
+func NewDelimitedWriter(w io.Writer, delimiter rune, quote QuoteMode) Writer {
+	return &csvWriter{
+		w:         w,
+		delimiter: delimiter,
+		quote:     quote,
+	}
+}
+
+func (c *csvWriter) WriteHeader(columns []string) error {
+	return c.writeLine(columns)
+}
+
+func (c *csvWriter) WriteRow(row []string) error {
+	return c.writeLine(row)
+}
+
+func (c *csvWriter) Close() error {
+	return nil
+}
+
+func (c *csvWriter) writeLine(items []string) error {
+	var b strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			b.WriteRune(c.delimiter)
+		}
+
+		if !c.needsQuotes(item) {
+			b.WriteString(item)
+			continue
+		}
+
+		b.WriteByte('"')
+		b.WriteString(strings.ReplaceAll(item, `"`, `""`))
+		b.WriteByte('"')
+	}
+	b.WriteByte('\n')
+
+	_, err := io.WriteString(c.w, b.String())
+	return err
+}
+
+// needsQuotes reports whether the field must be quoted, it follows encoding/csv rules in the minimal mode.
+func (c *csvWriter) needsQuotes(field string) bool {
+	switch c.quote {
+	case QuoteAll:
+		return true
+	case QuoteNone:
+		return false
+	}
+
+	if field == "" {
+		return false
+	}
+
+	if field == `\.` || strings.ContainsRune(field, c.delimiter) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r)
+}
+
+// tsvEscaper escapes special characters in tab separated values.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// tsvWriter writes tab separated values.
+type tsvWriter struct {
+	w io.Writer
+}
+
+func newTSVWriter(w io.Writer) *tsvWriter {
+	return &tsvWriter{w: w}
+}
+
+func (t *tsvWriter) WriteHeader(columns []string) error {
+	return t.writeLine(columns)
+}
+
+func (t *tsvWriter) WriteRow(row []string) error {
+	return t.writeLine(row)
+}
+
+func (t *tsvWriter) Close() error {
+	return nil
+}
+
+func (t *tsvWriter) writeLine(items []string) error {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = tsvEscaper.Replace(item)
+	}
+
+	_, err := io.WriteString(t.w, strings.Join(escaped, "\t")+"\n")
+	return err
+}
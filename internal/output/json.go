@@ -0,0 +1,95 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// jsonWriter writes rows as JSON objects keyed by column names.
+// The objects are wrapped in an array unless lines is true.
+type jsonWriter struct {
+	w       io.Writer
+	lines   bool
+	header  []string
+	started bool
+}
+
+func newJSONWriter(w io.Writer, lines bool) *jsonWriter {
+	return &jsonWriter{w: w, lines: lines}
+}
+
+func (j *jsonWriter) WriteHeader(columns []string) error {
+	j.header = columns
+	return nil
+}
+
+func (j *jsonWriter) WriteRow(row []string) error {
+	object, err := j.encodeObject(row)
+	if err != nil {
+		return err
+	}
+
+	prefix := ""
+	if !j.lines {
+		prefix = ",\n"
+		if !j.started {
+			prefix = "[\n"
+		}
+	}
+	j.started = true
+
+	if j.lines {
+		object = append(object, '\n')
+	}
+
+	_, err = io.WriteString(j.w, prefix+string(object))
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if j.lines {
+		return nil
+	}
+
+	end := "\n]\n"
+	if !j.started {
+		end = "[]\n"
+	}
+
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// encodeObject encodes the row keeping the column order.
+func (j *jsonWriter) encodeObject(row []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, value := range row {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		var key string
+		if i < len(j.header) {
+			key = j.header[i]
+		}
+
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedValue)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
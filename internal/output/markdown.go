@@ -0,0 +1,49 @@
+package output
+
+import (
+	"io"
+	"strings"
+)
+
+// markdownEscaper escapes characters which break Markdown table cells.
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// markdownWriter writes Markdown table.
+type markdownWriter struct {
+	w io.Writer
+}
+
+func newMarkdownWriter(w io.Writer) *markdownWriter {
+	return &markdownWriter{w: w}
+}
+
+func (m *markdownWriter) WriteHeader(columns []string) error {
+	if err := m.writeLine(columns); err != nil {
+		return err
+	}
+
+	separator := make([]string, len(columns))
+	for i := range separator {
+		separator[i] = "---"
+	}
+
+	return m.writeLine(separator)
+}
+
+func (m *markdownWriter) WriteRow(row []string) error {
+	return m.writeLine(row)
+}
+
+func (m *markdownWriter) Close() error {
+	return nil
+}
+
+func (m *markdownWriter) writeLine(items []string) error {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = markdownEscaper.Replace(item)
+	}
+
+	_, err := io.WriteString(m.w, "| "+strings.Join(escaped, " | ")+" |\n")
+	return err
+}
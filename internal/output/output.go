@@ -0,0 +1,79 @@
+// Package output writes query results in different formats.
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format describes result output format.
+type Format string
+
+const (
+	// FormatTable returns aligned text table format.
+	FormatTable Format = "table"
+	// FormatCSV returns comma separated values format.
+	FormatCSV Format = "csv"
+	// FormatTSV returns tab separated values format.
+	FormatTSV Format = "tsv"
+	// FormatJSON returns JSON array of objects format.
+	FormatJSON Format = "json"
+	// FormatNDJSON returns newline delimited JSON objects format.
+	FormatNDJSON Format = "ndjson"
+	// FormatMarkdown returns Markdown table format.
+	FormatMarkdown Format = "markdown"
+)
+
+// Formats contains list of supported formats.
+var Formats = []Format{
+	FormatTable,
+	FormatCSV,
+	FormatTSV,
+	FormatJSON,
+	FormatNDJSON,
+	FormatMarkdown,
+}
+
+// ErrUnknownFormat indicates that output format isn't supported.
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// Writer is the interface that writes a query result.
+// WriteHeader is called once before rows, Close finishes the output.
+type Writer interface {
+	WriteHeader(columns []string) error
+	WriteRow(row []string) error
+	Close() error
+}
+
+// ParseFormat returns the format by its case insensitive name.
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: '%s'", ErrUnknownFormat, name)
+}
+
+// NewWriter returns result writer for the format.
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatTable:
+		return NewTableWriter(w, DefaultPreviewRows), nil
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatTSV:
+		return newTSVWriter(w), nil
+	case FormatJSON:
+		return newJSONWriter(w, false), nil
+	case FormatNDJSON:
+		return newJSONWriter(w, true), nil
+	case FormatMarkdown:
+		return newMarkdownWriter(w), nil
+	}
+
+	return nil, fmt.Errorf("%w: '%s'", ErrUnknownFormat, format)
+}
@@ -0,0 +1,152 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("JSON")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJSON, format)
+
+	_, err = ParseFormat("xml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	writer, err := NewWriter("xml", new(bytes.Buffer))
+	assert.Nil(t, writer)
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestWriters(t *testing.T) {
+	header := []string{"id", "name"}
+	rows := [][]string{
+		{"1", "Bob"},
+		{"2", "Smith, \"Jr\"|\tII"},
+	}
+
+	tests := []struct {
+		format     Format
+		rows       [][]string
+		wantOutput string
+	}{
+		{
+			format:     FormatTable,
+			rows:       rows[:1],
+			wantOutput: "   id|   name|\n    1|    Bob|\n",
+		},
+		{
+			format:     FormatTable,
+			wantOutput: "",
+		},
+		{
+			format:     FormatCSV,
+			rows:       rows,
+			wantOutput: "id,name\n1,Bob\n2,\"Smith, \"\"Jr\"\"|\tII\"\n",
+		},
+		{
+			format:     FormatTSV,
+			rows:       rows,
+			wantOutput: "id\tname\n1\tBob\n2\tSmith, \"Jr\"|\\tII\n",
+		},
+		{
+			format:     FormatJSON,
+			rows:       rows,
+			wantOutput: "[\n{\"id\":\"1\",\"name\":\"Bob\"},\n{\"id\":\"2\",\"name\":\"Smith, \\\"Jr\\\"|\\tII\"}\n]\n",
+		},
+		{
+			format:     FormatJSON,
+			wantOutput: "[]\n",
+		},
+		{
+			format:     FormatNDJSON,
+			rows:       rows,
+			wantOutput: "{\"id\":\"1\",\"name\":\"Bob\"}\n{\"id\":\"2\",\"name\":\"Smith, \\\"Jr\\\"|\\tII\"}\n",
+		},
+		{
+			format:     FormatMarkdown,
+			rows:       rows,
+			wantOutput: "| id | name |\n| --- | --- |\n| 1 | Bob |\n| 2 | Smith, \"Jr\"\\|\tII |\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			writer, err := NewWriter(tt.format, buf)
+			assert.NoError(t, err)
+
+			assert.NoError(t, writer.WriteHeader(header))
+			for _, row := range tt.rows {
+				assert.NoError(t, writer.WriteRow(row))
+			}
+			assert.NoError(t, writer.Close())
+
+			assert.Equal(t, tt.wantOutput, buf.String())
+		})
+	}
+}
+
+func TestNewDelimitedWriter(t *testing.T) {
+	tests := []struct {
+		quote      QuoteMode
+		wantOutput string
+	}{
+		{
+			quote:      QuoteMinimal,
+			wantOutput: "id;name\n1;\"a;b\"\n2;\" c\"\n3;\n",
+		},
+		{
+			quote:      QuoteAll,
+			wantOutput: "\"id\";\"name\"\n\"1\";\"a;b\"\n\"2\";\" c\"\n\"3\";\"\"\n",
+		},
+		{
+			quote:      QuoteNone,
+			wantOutput: "id;name\n1;a;b\n2; c\n3;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.quote), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			writer := NewDelimitedWriter(buf, ';', tt.quote)
+
+			assert.NoError(t, writer.WriteHeader([]string{"id", "name"}))
+			assert.NoError(t, writer.WriteRow([]string{"1", "a;b"}))
+			assert.NoError(t, writer.WriteRow([]string{"2", " c"}))
+			assert.NoError(t, writer.WriteRow([]string{"3", ""}))
+			assert.NoError(t, writer.Close())
+
+			assert.Equal(t, tt.wantOutput, buf.String())
+		})
+	}
+}
+
+func TestParseQuoteMode(t *testing.T) {
+	mode, err := ParseQuoteMode("ALL")
+	assert.NoError(t, err)
+	assert.Equal(t, QuoteAll, mode)
+
+	_, err = ParseQuoteMode("sometimes")
+	assert.ErrorIs(t, err, ErrUnknownQuoteMode)
+}
+
+func TestNewTableWriter_Preview(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := NewTableWriter(buf, 2)
+
+	assert.NoError(t, writer.WriteHeader([]string{"id", "name"}))
+	assert.NoError(t, writer.WriteRow([]string{"1", "Bob"}))
+	assert.Empty(t, buf.String())
+
+	assert.NoError(t, writer.WriteRow([]string{"2", "Alice"}))
+	assert.Equal(t, "   id|    name|\n    1|     Bob|\n    2|   Alice|\n", buf.String())
+
+	assert.NoError(t, writer.WriteRow([]string{"3", "Christopher"}))
+	assert.NoError(t, writer.Close())
+	assert.Equal(t, "   id|    name|\n    1|     Bob|\n    2|   Alice|\n    3|   Christopher|\n", buf.String())
+}
@@ -0,0 +1,111 @@
+package output
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultPreviewRows contains count of rows which column widths of table format are computed from.
+	DefaultPreviewRows = 1000
+
+	tablePadding   = 3
+	tableSeparator = "|"
+)
+
+// tableWriter writes right aligned text table.
+// Column widths are computed from the header and the first preview rows,
+// longer values of the following rows aren't aligned.
+// Nothing is written if there are no rows.
+type tableWriter struct {
+	w           io.Writer
+	previewRows int
+	header      []string
+	preview     [][]string
+	widths      []int
+}
+
+// NewTableWriter returns text table writer which computes column widths from previewRows rows.
+func NewTableWriter(w io.Writer, previewRows int) Writer {
+	if previewRows <= 0 {
+		previewRows = DefaultPreviewRows
+	}
+
+	return &tableWriter{
+		w:           w,
+		previewRows: previewRows,
+	}
+}
+
+func (t *tableWriter) WriteHeader(columns []string) error {
+	t.header = columns
+	return nil
+}
+
+func (t *tableWriter) WriteRow(row []string) error {
+	if t.widths != nil {
+		return t.writeLine(row)
+	}
+
+	t.preview = append(t.preview, row)
+	if len(t.preview) < t.previewRows {
+		return nil
+	}
+
+	return t.flushPreview()
+}
+
+func (t *tableWriter) Close() error {
+	if t.widths != nil || len(t.preview) == 0 {
+		return nil
+	}
+
+	return t.flushPreview()
+}
+
+// flushPreview computes column widths and writes the header with the preview rows.
+func (t *tableWriter) flushPreview() error {
+	t.widths = make([]int, 0, len(t.header))
+	for _, line := range append([][]string{t.header}, t.preview...) {
+		for i, item := range line {
+			width := utf8.RuneCountInString(item) + tablePadding
+			if i >= len(t.widths) {
+				t.widths = append(t.widths, width)
+			} else if width > t.widths[i] {
+				t.widths[i] = width
+			}
+		}
+	}
+
+	if err := t.writeLine(t.header); err != nil {
+		return err
+	}
+
+	for _, row := range t.preview {
+		if err := t.writeLine(row); err != nil {
+			return err
+		}
+	}
+	t.preview = nil
+
+	return nil
+}
+
+func (t *tableWriter) writeLine(items []string) error {
+	var b strings.Builder
+	for i, item := range items {
+		width := utf8.RuneCountInString(item) + tablePadding
+		if i < len(t.widths) && t.widths[i] > width {
+			width = t.widths[i]
+		}
+
+		b.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(item)))
+		b.WriteString(item)
+		b.WriteString(tableSeparator)
+	}
+	b.WriteByte('\n')
+
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
@@ -0,0 +1,333 @@
+package parquet
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxPageValues limits the number of values of a page.
+const maxPageValues = 1 << 26
+
+// pageBufferSize is the size of the buffer pages of a column chunk are read with.
+const pageBufferSize = 64 << 10
+
+var (
+	zstdOnce    sync.Once
+	zstdDecoder *zstd.Decoder
+	errZstd     error
+)
+
+// ColumnReader reads values of a column chunk page by page, null values are empty.
+type ColumnReader struct {
+	column *Column
+	pages  *bufio.Reader
+	codec  int32
+	// remaining is the number of values in the pages which aren't read yet.
+	remaining  int64
+	dictionary []string
+
+	values []string
+	pos    int
+}
+
+func newColumnReader(column *Column, chunk io.Reader, meta columnMetaData) *ColumnReader {
+	return &ColumnReader{
+		column:    column,
+		pages:     bufio.NewReaderSize(chunk, pageBufferSize),
+		codec:     meta.codec,
+		remaining: meta.numValues,
+	}
+}
+
+// Next returns the next value, io.EOF after the last one.
+func (r *ColumnReader) Next() (string, error) {
+	for r.pos == len(r.values) {
+		if r.remaining <= 0 {
+			return "", io.EOF
+		}
+
+		err := r.readPage()
+		if err != nil {
+			return "", fmt.Errorf("column %s: %w", r.column.Name, err)
+		}
+	}
+
+	value := r.values[r.pos]
+	r.pos++
+
+	return value, nil
+}
+
+// readPage reads the next page, data pages replace the current values.
+func (r *ColumnReader) readPage() error {
+	header, err := (&thriftReader{r: r.pages}).readPageHeader()
+	if err != nil {
+		return err
+	}
+
+	if header.compressedSize < 0 || header.uncompressedSize < 0 {
+		return fmt.Errorf("%w: page size %d", ErrInvalidFile, header.compressedSize)
+	}
+
+	data := make([]byte, header.compressedSize)
+	_, err = io.ReadFull(r.pages, data)
+	if err != nil {
+		return errTruncated("page")
+	}
+
+	switch header.typ {
+	case pageDictionary:
+		return r.readDictionaryPage(header, data)
+	case pageData:
+		page, err := r.decompress(data, int(header.uncompressedSize))
+		if err != nil {
+			return err
+		}
+		return r.readDataPage(header.dataPage, page)
+	case pageDataV2:
+		return r.readDataPageV2(header, data)
+	}
+
+	// index pages and unknown pages are skipped
+	return nil
+}
+
+func (r *ColumnReader) readDictionaryPage(header pageHeader, data []byte) error {
+	h := header.dictionaryPage
+	if h.encoding != encodingPlain && h.encoding != encodingPlainDictionary {
+		return fmt.Errorf("%w: dictionary encoding %d", ErrUnsupported, h.encoding)
+	}
+
+	if h.numValues < 0 || h.numValues > maxPageValues {
+		return fmt.Errorf("%w: dictionary of %d values", ErrInvalidFile, h.numValues)
+	}
+
+	page, err := r.decompress(data, int(header.uncompressedSize))
+	if err != nil {
+		return err
+	}
+
+	r.dictionary, err = r.column.decodePlain(page, int(h.numValues))
+	return err
+}
+
+func (r *ColumnReader) readDataPage(h dataPageHeader, page []byte) error {
+	count, err := r.pageValues(h.numValues)
+	if err != nil {
+		return err
+	}
+
+	var levels []uint32
+	if r.column.maxDef > 0 {
+		if h.defEncoding != encodingRLE {
+			return fmt.Errorf("%w: definition level encoding %d", ErrUnsupported, h.defEncoding)
+		}
+
+		if len(page) < 4 {
+			return errTruncated("definition levels")
+		}
+		size := binary.LittleEndian.Uint32(page)
+		if uint64(len(page)-4) < uint64(size) {
+			return errTruncated("definition levels")
+		}
+
+		levels, err = decodeHybrid(page[4:4+size], bits.Len(uint(r.column.maxDef)), count)
+		if err != nil {
+			return err
+		}
+		page = page[4+size:]
+	}
+
+	return r.decodeValues(h.encoding, page, levels, count)
+}
+
+func (r *ColumnReader) readDataPageV2(header pageHeader, data []byte) error {
+	h := header.dataPageV2
+	count, err := r.pageValues(h.numValues)
+	if err != nil {
+		return err
+	}
+
+	if h.repLength < 0 || h.defLength < 0 || int64(h.repLength)+int64(h.defLength) > int64(len(data)) {
+		return errTruncated("levels")
+	}
+
+	var levels []uint32
+	if r.column.maxDef > 0 {
+		levels, err = decodeHybrid(data[h.repLength:h.repLength+h.defLength], bits.Len(uint(r.column.maxDef)), count)
+		if err != nil {
+			return err
+		}
+	}
+
+	page := data[h.repLength+h.defLength:]
+	if h.isCompressed {
+		size := int(header.uncompressedSize) - int(h.repLength+h.defLength)
+		page, err = r.decompress(page, size)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.decodeValues(h.encoding, page, levels, count)
+}
+
+// pageValues checks the number of values of a data page.
+func (r *ColumnReader) pageValues(count int32) (int, error) {
+	if count < 0 || count > maxPageValues || int64(count) > r.remaining {
+		return 0, fmt.Errorf("%w: page of %d values", ErrInvalidFile, count)
+	}
+
+	r.remaining -= int64(count)
+	return int(count), nil
+}
+
+// decodeValues decodes values of a data page, values are absent for levels below the maximum definition level.
+func (r *ColumnReader) decodeValues(encoding int32, data []byte, levels []uint32, count int) error {
+	present := count
+	if levels != nil {
+		present = 0
+		for _, level := range levels {
+			if int(level) == r.column.maxDef {
+				present++
+			}
+		}
+	}
+
+	values, err := r.decode(encoding, data, present)
+	if err != nil {
+		return err
+	}
+
+	if levels != nil {
+		all := make([]string, count)
+		next := 0
+		for i, level := range levels {
+			if int(level) == r.column.maxDef {
+				all[i] = values[next]
+				next++
+			}
+		}
+		values = all
+	}
+
+	r.values = values
+	r.pos = 0
+
+	return nil
+}
+
+func (r *ColumnReader) decode(encoding int32, data []byte, count int) ([]string, error) {
+	c := r.column
+	switch encoding {
+	case encodingPlain:
+		return c.decodePlain(data, count)
+	case encodingPlainDictionary, encodingRLEDictionary:
+		return r.decodeDictionary(data, count)
+	case encodingRLE:
+		if c.element.typ != typeBoolean {
+			break
+		}
+		if len(data) < 4 {
+			return nil, errTruncated("boolean values")
+		}
+		indexes, err := decodeHybrid(data[4:], 1, count)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, count)
+		for i, v := range indexes {
+			values[i] = formatBoolean(v == 1)
+		}
+		return values, nil
+	}
+
+	name, ok := encodingNames[encoding]
+	if !ok {
+		name = fmt.Sprint(encoding)
+	}
+	return nil, fmt.Errorf("%w: %s encoding of column %s", ErrUnsupported, name, c.Name)
+}
+
+// decodeDictionary decodes indexes of dictionary values, the bit width of indexes is the first byte.
+func (r *ColumnReader) decodeDictionary(data []byte, count int) ([]string, error) {
+	if r.dictionary == nil {
+		return nil, fmt.Errorf("%w: dictionary page is missing", ErrInvalidFile)
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	if len(data) == 0 {
+		return nil, errTruncated("dictionary indexes")
+	}
+
+	indexes, err := decodeHybrid(data[1:], int(data[0]), count)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, count)
+	for i, index := range indexes {
+		if int(index) >= len(r.dictionary) {
+			return nil, fmt.Errorf("%w: dictionary index %d of %d values", ErrInvalidFile, index, len(r.dictionary))
+		}
+		values[i] = r.dictionary[index]
+	}
+
+	return values, nil
+}
+
+// decompress decompresses the page data of the size.
+func (r *ColumnReader) decompress(data []byte, size int) ([]byte, error) {
+	var page []byte
+	var err error
+
+	switch r.codec {
+	case codecUncompressed:
+		return data, nil
+	case codecSnappy:
+		page, err = snappy.Decode(nil, data)
+	case codecGzip:
+		var reader *gzip.Reader
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+		if err == nil {
+			page = make([]byte, size)
+			_, err = io.ReadFull(reader, page)
+		}
+	case codecZstd:
+		zstdOnce.Do(func() {
+			zstdDecoder, errZstd = zstd.NewReader(nil)
+		})
+		if errZstd != nil {
+			return nil, errZstd
+		}
+		page, err = zstdDecoder.DecodeAll(data, make([]byte, 0, size))
+	default:
+		name, ok := codecNames[r.codec]
+		if !ok {
+			name = fmt.Sprint(r.codec)
+		}
+		return nil, fmt.Errorf("%w: %s compression", ErrUnsupported, name)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	if len(page) != size {
+		return nil, fmt.Errorf("%w: page of %d bytes, %d expected", ErrInvalidFile, len(page), size)
+	}
+
+	return page, nil
+}
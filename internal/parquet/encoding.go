@@ -0,0 +1,174 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// decodePlain decodes count plain encoded values.
+func (c *Column) decodePlain(data []byte, count int) ([]string, error) {
+	switch c.element.typ {
+	case typeBoolean:
+		if len(data)*8 < count {
+			return nil, errTruncated("boolean values")
+		}
+		values := make([]string, count)
+		for i := range values {
+			values[i] = formatBoolean(data[i/8]>>(i%8)&1 == 1)
+		}
+		return values, nil
+	case typeByteArray:
+		if len(data)/4 < count {
+			return nil, errTruncated("byte arrays")
+		}
+		values := make([]string, count)
+		for i := range values {
+			if len(data) < 4 {
+				return nil, errTruncated("byte arrays")
+			}
+			size := binary.LittleEndian.Uint32(data)
+			data = data[4:]
+			if uint64(len(data)) < uint64(size) {
+				return nil, errTruncated("byte arrays")
+			}
+			values[i] = c.formatBytes(data[:size])
+			data = data[size:]
+		}
+		return values, nil
+	}
+
+	size, err := c.valueSize()
+	if err != nil {
+		return nil, err
+	}
+	if len(data)/size < count {
+		return nil, errTruncated("values")
+	}
+
+	values := make([]string, count)
+	for i := range values {
+		values[i] = c.formatFixed(data[i*size : (i+1)*size])
+	}
+
+	return values, nil
+}
+
+// valueSize returns the size of plain encoded values of fixed size types.
+func (c *Column) valueSize() (int, error) {
+	switch c.element.typ {
+	case typeInt32, typeFloat:
+		return 4, nil
+	case typeInt64, typeDouble:
+		return 8, nil
+	case typeInt96:
+		return 12, nil
+	case typeFixedLenByteArray:
+		if c.element.typeLength <= 0 {
+			return 0, fmt.Errorf("%w: column %s has length %d", ErrInvalidFile, c.Name, c.element.typeLength)
+		}
+		return int(c.element.typeLength), nil
+	}
+
+	return 0, fmt.Errorf("%w: column %s has type %d", ErrInvalidFile, c.Name, c.element.typ)
+}
+
+// formatFixed formats a plain encoded value of a fixed size type.
+func (c *Column) formatFixed(v []byte) string {
+	switch c.element.typ {
+	case typeInt32:
+		return c.formatInt32(int32(binary.LittleEndian.Uint32(v)))
+	case typeInt64:
+		return c.formatInt64(int64(binary.LittleEndian.Uint64(v)))
+	case typeInt96:
+		return formatInt96(v)
+	case typeFloat:
+		return formatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(v))), 32)
+	case typeDouble:
+		return formatFloat(math.Float64frombits(binary.LittleEndian.Uint64(v)), 64)
+	}
+
+	return c.formatBytes(v)
+}
+
+// decodeHybrid decodes count values of the RLE and bit-packing hybrid encoding.
+func decodeHybrid(data []byte, bitWidth, count int) ([]uint32, error) {
+	if bitWidth > 32 {
+		return nil, fmt.Errorf("%w: bit width %d", ErrInvalidFile, bitWidth)
+	}
+
+	byteWidth := (bitWidth + 7) / 8
+	values := make([]uint32, 0, count)
+
+	for len(values) < count {
+		header, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errTruncated("RLE runs")
+		}
+		data = data[n:]
+
+		if header&1 == 0 {
+			if len(data) < byteWidth {
+				return nil, errTruncated("RLE runs")
+			}
+
+			var value uint32
+			for i := 0; i < byteWidth; i++ {
+				value |= uint32(data[i]) << (8 * i)
+			}
+			data = data[byteWidth:]
+
+			run := header >> 1
+			if left := uint64(count - len(values)); run > left {
+				run = left
+			}
+			for i := uint64(0); i < run; i++ {
+				values = append(values, value)
+			}
+			continue
+		}
+
+		groups := header >> 1
+		size := groups * uint64(bitWidth)
+		if size > uint64(len(data)) {
+			// the last bit-packed run may be cut
+			size = uint64(len(data))
+		}
+
+		packed := int(size) * 8 / max(bitWidth, 1)
+		if bitWidth == 0 {
+			packed = int(min(groups*8, uint64(count)))
+		}
+		if left := count - len(values); packed > left {
+			packed = left
+		}
+		if packed == 0 {
+			return nil, errTruncated("bit-packed runs")
+		}
+
+		for i := 0; i < packed; i++ {
+			values = append(values, uint32(unpack(data, i, bitWidth)))
+		}
+		data = data[size:]
+	}
+
+	return values, nil
+}
+
+// unpack returns the value at the index of values packed with the bit width starting from the least significant bit.
+func unpack(data []byte, index, bitWidth int) uint64 {
+	var value uint64
+	offset := index * bitWidth
+	for read := 0; read < bitWidth; {
+		bit := (offset + read) % 8
+		take := min(8-bit, bitWidth-read)
+		value |= uint64(data[(offset+read)/8]>>bit&(1<<take-1)) << read
+		read += take
+	}
+
+	return value
+}
+
+func errTruncated(what string) error {
+	return fmt.Errorf("%w: truncated %s", ErrInvalidFile, what)
+}
@@ -0,0 +1,65 @@
+package parquet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeHybrid(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		bitWidth int
+		count    int
+		want     []uint32
+	}{
+		{
+			name:     "bit-packed",
+			data:     []byte{0x03, 0x88, 0xC6, 0xFA},
+			bitWidth: 3,
+			count:    8,
+			want:     []uint32{0, 1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			name:     "RLE run",
+			data:     []byte{0x0A, 0x07},
+			bitWidth: 3,
+			count:    5,
+			want:     []uint32{7, 7, 7, 7, 7},
+		},
+		{
+			name:     "runs of both kinds",
+			data:     []byte{0x04, 0x01, 0x03, 0x0A},
+			bitWidth: 1,
+			count:    5,
+			want:     []uint32{1, 1, 0, 1, 0},
+		},
+		{
+			name:     "wide RLE value",
+			data:     []byte{0x04, 0x34, 0x12},
+			bitWidth: 13,
+			count:    2,
+			want:     []uint32{0x1234, 0x1234},
+		},
+		{
+			name:     "zero bit width",
+			data:     []byte{0x03},
+			bitWidth: 0,
+			count:    3,
+			want:     []uint32{0, 0, 0},
+		},
+	}
+
+	for _, test := range tests {
+		values, err := decodeHybrid(test.data, test.bitWidth, test.count)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.want, values, test.name)
+	}
+
+	_, err := decodeHybrid([]byte{0x0A, 0x07}, 3, 6)
+	assert.ErrorIs(t, err, ErrInvalidFile)
+
+	_, err = decodeHybrid([]byte{0x03}, 33, 1)
+	assert.ErrorIs(t, err, ErrInvalidFile)
+}
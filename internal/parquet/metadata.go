@@ -0,0 +1,454 @@
+package parquet
+
+// Physical types of values.
+const (
+	typeBoolean           = 0
+	typeInt32             = 1
+	typeInt64             = 2
+	typeInt96             = 3
+	typeFloat             = 4
+	typeDouble            = 5
+	typeByteArray         = 6
+	typeFixedLenByteArray = 7
+)
+
+// Converted types, the legacy annotations of physical types.
+const (
+	convertedUTF8            = 0
+	convertedEnum            = 4
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimeMillis      = 7
+	convertedTimeMicros      = 8
+	convertedTimestampMillis = 9
+	convertedTimestampMicros = 10
+	convertedUint8           = 11
+	convertedUint16          = 12
+	convertedUint32          = 13
+	convertedUint64          = 14
+	convertedJSON            = 19
+	convertedInterval        = 21
+)
+
+// Logical types, they are the ids of the LogicalType union fields.
+const (
+	logicalString    = 1
+	logicalEnum      = 4
+	logicalDecimal   = 5
+	logicalDate      = 6
+	logicalTime      = 7
+	logicalTimestamp = 8
+	logicalInteger   = 10
+	logicalJSON      = 12
+	logicalUUID      = 14
+	logicalFloat16   = 15
+)
+
+// Time units of time and timestamp logical types.
+const (
+	unitMillis = 1
+	unitMicros = 2
+	unitNanos  = 3
+)
+
+// Repetition types of schema elements.
+const (
+	repetitionOptional = 1
+	repetitionRepeated = 2
+)
+
+// Encodings of values and levels, the delta and byte stream split encodings aren't supported.
+const (
+	encodingPlain           = 0
+	encodingPlainDictionary = 2
+	encodingRLE             = 3
+	encodingRLEDictionary   = 8
+)
+
+// encodingNames names the encodings in errors.
+var encodingNames = map[int32]string{
+	0: "PLAIN", 2: "PLAIN_DICTIONARY", 3: "RLE", 4: "BIT_PACKED", 5: "DELTA_BINARY_PACKED",
+	6: "DELTA_LENGTH_BYTE_ARRAY", 7: "DELTA_BYTE_ARRAY", 8: "RLE_DICTIONARY", 9: "BYTE_STREAM_SPLIT",
+}
+
+// Compression codecs of pages.
+const (
+	codecUncompressed = 0
+	codecSnappy       = 1
+	codecGzip         = 2
+	codecZstd         = 6
+)
+
+// codecNames names the codecs in errors.
+var codecNames = map[int32]string{
+	0: "UNCOMPRESSED", 1: "SNAPPY", 2: "GZIP", 3: "LZO", 4: "BROTLI", 5: "LZ4", 6: "ZSTD", 7: "LZ4_RAW",
+}
+
+// Page types.
+const (
+	pageData       = 0
+	pageDictionary = 2
+	pageDataV2     = 3
+)
+
+// fileMetaData is the footer of a Parquet file.
+type fileMetaData struct {
+	schema    []schemaElement
+	numRows   int64
+	rowGroups []rowGroup
+}
+
+// schemaElement is a node of the schema tree written in depth-first order, leaves are columns.
+type schemaElement struct {
+	typ           int32
+	typeLength    int32
+	repetition    int32
+	name          string
+	numChildren   int32
+	convertedType int32
+	hasConverted  bool
+	scale         int32
+	logical       logicalType
+}
+
+// logicalType is the annotation of a physical type, kind is zero if it isn't set.
+type logicalType struct {
+	kind     int16
+	scale    int32
+	unit     int16
+	bitWidth int8
+	signed   bool
+}
+
+type rowGroup struct {
+	columns []columnChunk
+	numRows int64
+}
+
+type columnChunk struct {
+	filePath string
+	meta     columnMetaData
+}
+
+type columnMetaData struct {
+	typ                  int32
+	codec                int32
+	numValues            int64
+	totalCompressedSize  int64
+	dataPageOffset       int64
+	dictionaryPageOffset int64
+	statistics           statistics
+}
+
+// statistics of a column chunk, min and max are deprecated and ordered as signed values.
+type statistics struct {
+	max          []byte
+	min          []byte
+	nullCount    int64
+	hasNullCount bool
+	maxValue     []byte
+	minValue     []byte
+}
+
+type pageHeader struct {
+	typ              int32
+	uncompressedSize int32
+	compressedSize   int32
+	dataPage         dataPageHeader
+	dataPageV2       dataPageHeaderV2
+	dictionaryPage   dictionaryPageHeader
+}
+
+type dataPageHeader struct {
+	numValues   int32
+	encoding    int32
+	defEncoding int32
+}
+
+type dataPageHeaderV2 struct {
+	numValues    int32
+	encoding     int32
+	defLength    int32
+	repLength    int32
+	isCompressed bool
+}
+
+type dictionaryPageHeader struct {
+	numValues int32
+	encoding  int32
+}
+
+func (t *thriftReader) readFileMetaData() (fileMetaData, error) {
+	var m fileMetaData
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 2 && typ == thriftList:
+			return t.readList(func(byte) error {
+				element, err := t.readSchemaElement()
+				m.schema = append(m.schema, element)
+				return err
+			})
+		case id == 3 && typ == thriftI64:
+			m.numRows, err = t.readInt()
+		case id == 4 && typ == thriftList:
+			return t.readList(func(byte) error {
+				group, err := t.readRowGroup()
+				m.rowGroups = append(m.rowGroups, group)
+				return err
+			})
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return m, err
+}
+
+func (t *thriftReader) readSchemaElement() (schemaElement, error) {
+	e := schemaElement{typ: -1}
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			e.typ, err = t.readI32()
+		case id == 2 && typ == thriftI32:
+			e.typeLength, err = t.readI32()
+		case id == 3 && typ == thriftI32:
+			e.repetition, err = t.readI32()
+		case id == 4 && typ == thriftBinary:
+			e.name, err = t.readString()
+		case id == 5 && typ == thriftI32:
+			e.numChildren, err = t.readI32()
+		case id == 6 && typ == thriftI32:
+			e.convertedType, err = t.readI32()
+			e.hasConverted = true
+		case id == 7 && typ == thriftI32:
+			e.scale, err = t.readI32()
+		case id == 10 && typ == thriftStruct:
+			e.logical, err = t.readLogicalType()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return e, err
+}
+
+func (t *thriftReader) readLogicalType() (logicalType, error) {
+	var l logicalType
+	err := t.readStruct(func(id int16, typ byte) error {
+		if typ != thriftStruct {
+			return t.skip(typ)
+		}
+
+		l.kind = id
+		return t.readStruct(func(field int16, typ byte) error {
+			var err error
+			switch {
+			case id == logicalDecimal && field == 1 && typ == thriftI32:
+				l.scale, err = t.readI32()
+			case (id == logicalTime || id == logicalTimestamp) && field == 2 && typ == thriftStruct:
+				err = t.readStruct(func(unit int16, typ byte) error {
+					l.unit = unit
+					return t.skip(typ)
+				})
+			case id == logicalInteger && field == 1 && typ == thriftByte:
+				var b byte
+				b, err = t.r.ReadByte()
+				l.bitWidth = int8(b)
+				err = unexpectedEOF(err)
+			case id == logicalInteger && field == 2:
+				l.signed = typ == thriftTrue
+			default:
+				err = t.skip(typ)
+			}
+			return err
+		})
+	})
+
+	return l, err
+}
+
+func (t *thriftReader) readRowGroup() (rowGroup, error) {
+	var g rowGroup
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftList:
+			return t.readList(func(byte) error {
+				chunk, err := t.readColumnChunk()
+				g.columns = append(g.columns, chunk)
+				return err
+			})
+		case id == 3 && typ == thriftI64:
+			g.numRows, err = t.readInt()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return g, err
+}
+
+func (t *thriftReader) readColumnChunk() (columnChunk, error) {
+	var c columnChunk
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftBinary:
+			c.filePath, err = t.readString()
+		case id == 3 && typ == thriftStruct:
+			c.meta, err = t.readColumnMetaData()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return c, err
+}
+
+func (t *thriftReader) readColumnMetaData() (columnMetaData, error) {
+	var m columnMetaData
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			m.typ, err = t.readI32()
+		case id == 4 && typ == thriftI32:
+			m.codec, err = t.readI32()
+		case id == 5 && typ == thriftI64:
+			m.numValues, err = t.readInt()
+		case id == 7 && typ == thriftI64:
+			m.totalCompressedSize, err = t.readInt()
+		case id == 9 && typ == thriftI64:
+			m.dataPageOffset, err = t.readInt()
+		case id == 11 && typ == thriftI64:
+			m.dictionaryPageOffset, err = t.readInt()
+		case id == 12 && typ == thriftStruct:
+			m.statistics, err = t.readStatistics()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return m, err
+}
+
+func (t *thriftReader) readStatistics() (statistics, error) {
+	var s statistics
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftBinary:
+			s.max, err = t.readBinary()
+		case id == 2 && typ == thriftBinary:
+			s.min, err = t.readBinary()
+		case id == 3 && typ == thriftI64:
+			s.nullCount, err = t.readInt()
+			s.hasNullCount = true
+		case id == 5 && typ == thriftBinary:
+			s.maxValue, err = t.readBinary()
+		case id == 6 && typ == thriftBinary:
+			s.minValue, err = t.readBinary()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return s, err
+}
+
+func (t *thriftReader) readPageHeader() (pageHeader, error) {
+	var h pageHeader
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			h.typ, err = t.readI32()
+		case id == 2 && typ == thriftI32:
+			h.uncompressedSize, err = t.readI32()
+		case id == 3 && typ == thriftI32:
+			h.compressedSize, err = t.readI32()
+		case id == 5 && typ == thriftStruct:
+			h.dataPage, err = t.readDataPageHeader()
+		case id == 7 && typ == thriftStruct:
+			h.dictionaryPage, err = t.readDictionaryPageHeader()
+		case id == 8 && typ == thriftStruct:
+			h.dataPageV2, err = t.readDataPageHeaderV2()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return h, err
+}
+
+func (t *thriftReader) readDataPageHeader() (dataPageHeader, error) {
+	var h dataPageHeader
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			h.numValues, err = t.readI32()
+		case id == 2 && typ == thriftI32:
+			h.encoding, err = t.readI32()
+		case id == 3 && typ == thriftI32:
+			h.defEncoding, err = t.readI32()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return h, err
+}
+
+func (t *thriftReader) readDataPageHeaderV2() (dataPageHeaderV2, error) {
+	h := dataPageHeaderV2{isCompressed: true}
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			h.numValues, err = t.readI32()
+		case id == 4 && typ == thriftI32:
+			h.encoding, err = t.readI32()
+		case id == 5 && typ == thriftI32:
+			h.defLength, err = t.readI32()
+		case id == 6 && typ == thriftI32:
+			h.repLength, err = t.readI32()
+		case id == 7 && (typ == thriftTrue || typ == thriftFalse):
+			h.isCompressed = typ == thriftTrue
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return h, err
+}
+
+func (t *thriftReader) readDictionaryPageHeader() (dictionaryPageHeader, error) {
+	var h dictionaryPageHeader
+	err := t.readStruct(func(id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			h.numValues, err = t.readI32()
+		case id == 2 && typ == thriftI32:
+			h.encoding, err = t.readI32()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+
+	return h, err
+}
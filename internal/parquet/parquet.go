@@ -0,0 +1,311 @@
+// Package parquet reads columns of Parquet files, values are returned as text.
+// Nested groups are flattened to dotted column names, repeated columns aren't supported.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrInvalidFile indicates that the file isn't a Parquet file or is corrupted.
+	ErrInvalidFile = errors.New("invalid parquet file")
+	// ErrUnsupported indicates that the file uses a feature which isn't supported, e.g. an unknown codec.
+	ErrUnsupported = errors.New("unsupported parquet feature")
+)
+
+// magic starts and ends Parquet files, encrypted files end with another magic.
+const (
+	magic          = "PAR1"
+	encryptedMagic = "PARE"
+)
+
+// footerSize is the size of the metadata length and the magic at the file end.
+const footerSize = 8
+
+// Kind tells how formatted values of a column are ordered and which conditions statistics can be used for.
+type Kind int
+
+const (
+	// KindOther columns have no usable order.
+	KindOther Kind = iota
+	// KindNumber columns have numbers ordered as numbers.
+	KindNumber
+	// KindText columns have values ordered as strings.
+	KindText
+)
+
+// File is an opened Parquet file.
+type File struct {
+	reader  io.ReaderAt
+	size    int64
+	Columns []Column
+	// RowGroups are horizontal partitions of the file, every one contains a chunk of each column.
+	RowGroups []RowGroup
+	NumRows   int64
+}
+
+// Column describes a leaf of the file schema.
+type Column struct {
+	// Name is the path of the column in the schema, names of nested fields are joined with dots.
+	Name    string
+	element schemaElement
+	maxDef  int
+	maxRep  int
+}
+
+// RowGroup is a horizontal partition of the file.
+type RowGroup struct {
+	NumRows int64
+	file    *File
+	chunks  []columnChunk
+}
+
+// Stats describes values of a column chunk, the minimum and the maximum are formatted like the values.
+type Stats struct {
+	Min          string
+	Max          string
+	HasMinMax    bool
+	NullCount    int64
+	HasNullCount bool
+}
+
+// Open reads the metadata of the file of the size.
+func Open(reader io.ReaderAt, size int64) (*File, error) {
+	if size < int64(len(magic)+footerSize) {
+		return nil, fmt.Errorf("%w: file is too small", ErrInvalidFile)
+	}
+
+	footer := make([]byte, footerSize)
+	_, err := reader.ReadAt(footer, size-footerSize)
+	if err != nil {
+		return nil, err
+	}
+
+	switch string(footer[4:]) {
+	case magic:
+	case encryptedMagic:
+		return nil, fmt.Errorf("%w: encrypted footer", ErrUnsupported)
+	default:
+		return nil, fmt.Errorf("%w: no magic bytes", ErrInvalidFile)
+	}
+
+	length := int64(binary.LittleEndian.Uint32(footer))
+	if length > size-int64(len(magic)+footerSize) {
+		return nil, fmt.Errorf("%w: metadata length %d", ErrInvalidFile, length)
+	}
+
+	buf := make([]byte, length)
+	_, err = reader.ReadAt(buf, size-footerSize-length)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := (&thriftReader{r: bytes.NewReader(buf)}).readFileMetaData()
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{reader: reader, size: size, NumRows: metadata.numRows}
+
+	err = file.readSchema(metadata.schema)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range metadata.rowGroups {
+		if len(group.columns) != len(file.Columns) {
+			return nil, fmt.Errorf("%w: row group has %d columns, schema has %d", ErrInvalidFile, len(group.columns), len(file.Columns))
+		}
+		file.RowGroups = append(file.RowGroups, RowGroup{NumRows: group.numRows, file: file, chunks: group.columns})
+	}
+
+	return file, nil
+}
+
+// readSchema collects leaves of the schema tree, the first element is the root.
+func (f *File) readSchema(schema []schemaElement) error {
+	if len(schema) == 0 {
+		return fmt.Errorf("%w: empty schema", ErrInvalidFile)
+	}
+
+	next, err := f.readSchemaChildren(schema, 1, int(schema[0].numChildren), "", 0, 0)
+	if err != nil {
+		return err
+	}
+
+	if next != len(schema) {
+		return fmt.Errorf("%w: schema has %d unused elements", ErrInvalidFile, len(schema)-next)
+	}
+
+	return nil
+}
+
+// readSchemaChildren reads count children starting at the index and returns the index after them.
+func (f *File) readSchemaChildren(schema []schemaElement, index, count int, prefix string, def, rep int) (int, error) {
+	for i := 0; i < count; i++ {
+		if index >= len(schema) {
+			return 0, fmt.Errorf("%w: schema is truncated", ErrInvalidFile)
+		}
+
+		element := schema[index]
+		index++
+
+		elementDef, elementRep := def, rep
+		switch element.repetition {
+		case repetitionOptional:
+			elementDef++
+		case repetitionRepeated:
+			elementDef++
+			elementRep++
+		}
+
+		name := prefix + element.name
+		if element.numChildren == 0 {
+			f.Columns = append(f.Columns, Column{Name: name, element: element, maxDef: elementDef, maxRep: elementRep})
+			continue
+		}
+
+		var err error
+		index, err = f.readSchemaChildren(schema, index, int(element.numChildren), name+".", elementDef, elementRep)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return index, nil
+}
+
+// Kind returns how formatted values of the column are ordered.
+func (c *Column) Kind() Kind {
+	e := c.element
+	switch e.typ {
+	case typeInt32, typeInt64:
+		if c.isDate() {
+			return KindText
+		}
+		if c.isTime() || c.timestampUnit() != 0 {
+			return KindOther
+		}
+		return KindNumber
+	case typeFloat, typeDouble:
+		return KindNumber
+	case typeBoolean:
+		return KindText
+	case typeByteArray, typeFixedLenByteArray:
+		switch {
+		case c.isDecimal():
+			return KindNumber
+		case e.logical.kind == logicalFloat16, e.hasConverted && e.convertedType == convertedInterval:
+			return KindOther
+		}
+		return KindText
+	}
+
+	return KindOther
+}
+
+// Nullable returns true if values of the column may be null.
+func (c *Column) Nullable() bool {
+	return c.maxDef > 0
+}
+
+// Stats returns the statistics of the column chunk. The deprecated minimum and maximum are used only
+// for types which they are correctly ordered for.
+func (g *RowGroup) Stats(column int) Stats {
+	c := &g.file.Columns[column]
+	s := g.chunks[column].meta.statistics
+	stats := Stats{NullCount: s.nullCount, HasNullCount: s.hasNullCount}
+
+	min, max := s.minValue, s.maxValue
+	if (min == nil || max == nil) && c.signedStats() {
+		min, max = s.min, s.max
+	}
+	if min == nil || max == nil {
+		return stats
+	}
+
+	var err error
+	stats.Min, err = c.formatStat(min)
+	if err != nil {
+		return stats
+	}
+
+	stats.Max, err = c.formatStat(max)
+	if err != nil {
+		return stats
+	}
+
+	// years beyond 9999 break the string order of formatted dates
+	if c.isDate() && (len(stats.Min) != len("2006-01-02") || len(stats.Max) != len("2006-01-02")) {
+		return stats
+	}
+
+	stats.HasMinMax = true
+	return stats
+}
+
+// signedStats returns true if the deprecated statistics of the column are correct, they are compared as signed values.
+func (c *Column) signedStats() bool {
+	switch c.element.typ {
+	case typeBoolean, typeFloat, typeDouble:
+		return true
+	case typeInt32, typeInt64:
+		return !c.isUnsigned()
+	}
+
+	return false
+}
+
+// formatStat formats a minimum or a maximum value, they are plain encoded without the length of byte arrays.
+func (c *Column) formatStat(value []byte) (string, error) {
+	switch c.element.typ {
+	case typeByteArray, typeFixedLenByteArray:
+		return c.formatBytes(value), nil
+	case typeBoolean:
+		if len(value) != 1 {
+			return "", ErrInvalidFile
+		}
+		return formatBoolean(value[0] != 0), nil
+	}
+
+	values, err := c.decodePlain(value, 1)
+	if err != nil {
+		return "", err
+	}
+
+	return values[0], nil
+}
+
+// Column returns the reader of the column chunk.
+func (g *RowGroup) Column(column int) (*ColumnReader, error) {
+	c := &g.file.Columns[column]
+	chunk := g.chunks[column]
+
+	if c.maxRep > 0 {
+		return nil, fmt.Errorf("%w: repeated column %s", ErrUnsupported, c.Name)
+	}
+
+	if chunk.filePath != "" {
+		return nil, fmt.Errorf("%w: column %s is stored in %s", ErrUnsupported, c.Name, chunk.filePath)
+	}
+
+	meta := chunk.meta
+	offset := meta.dataPageOffset
+	if meta.dictionaryPageOffset > 0 && meta.dictionaryPageOffset < offset {
+		offset = meta.dictionaryPageOffset
+	}
+
+	if offset < int64(len(magic)) || meta.totalCompressedSize < 0 || offset+meta.totalCompressedSize > g.file.size {
+		return nil, fmt.Errorf("%w: column %s chunk is out of the file", ErrInvalidFile, c.Name)
+	}
+
+	if meta.numValues != g.NumRows {
+		return nil, fmt.Errorf("%w: column %s has %d values in a row group of %d rows", ErrInvalidFile, c.Name, meta.numValues, g.NumRows)
+	}
+
+	return newColumnReader(c, io.NewSectionReader(g.file.reader, offset, meta.totalCompressedSize), meta), nil
+}
@@ -0,0 +1,187 @@
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/phpCoder88/csv-searcher/internal/parquet/parquettest"
+)
+
+var testColumns = []parquettest.Column{
+	{Name: "id", Type: parquettest.Int64},
+	{Name: "name", Type: parquettest.String, Optional: true},
+	{Name: "score", Type: parquettest.Double, Optional: true},
+	{Name: "active", Type: parquettest.Boolean},
+	{Name: "born", Type: parquettest.Date, Optional: true},
+	{Name: "level", Type: parquettest.Int32},
+}
+
+var testRows = [][]interface{}{
+	{int64(1), "Alice", 4.5, true, int32(18993), int32(3)},
+	{int64(2), nil, nil, false, nil, int32(3)},
+	{int64(3), "Bob", -1.25, true, int32(0), int32(-7)},
+	{int64(4), "Alice", 10.0, false, int32(-1), int32(3)},
+	{int64(5), "", 0.0, true, nil, int32(100)},
+}
+
+var testValues = [][]string{
+	{"1", "Alice", "4.5", "true", "2022-01-01", "3"},
+	{"2", "", "", "false", "", "3"},
+	{"3", "Bob", "-1.25", "true", "1970-01-01", "-7"},
+	{"4", "Alice", "10", "false", "1969-12-31", "3"},
+	{"5", "", "0", "true", "", "100"},
+}
+
+func openTestFile(t *testing.T, rows [][]interface{}, options parquettest.Options) *File {
+	t.Helper()
+
+	data, err := parquettest.Write(testColumns, rows, options)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	file, err := Open(bytes.NewReader(data), int64(len(data)))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return file
+}
+
+// readAll reads rows of all row groups.
+func readAll(file *File) ([][]string, error) {
+	var rows [][]string
+	for g := range file.RowGroups {
+		group := &file.RowGroups[g]
+		columns := make([][]string, len(file.Columns))
+		for i := range file.Columns {
+			reader, err := group.Column(i)
+			if err != nil {
+				return nil, err
+			}
+
+			for {
+				value, err := reader.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return nil, err
+				}
+				columns[i] = append(columns[i], value)
+			}
+		}
+
+		for r := int64(0); r < group.NumRows; r++ {
+			row := make([]string, len(columns))
+			for i := range columns {
+				row[i] = columns[i][r]
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+func TestFile_read(t *testing.T) {
+	tests := []parquettest.Options{
+		{},
+		{Codec: parquettest.Snappy},
+		{Codec: parquettest.Gzip, PageV2: true},
+		{Codec: parquettest.Zstd, Dictionary: true},
+		{Codec: parquettest.Snappy, Dictionary: true, PageV2: true, PageSize: 2},
+		{RowGroupSize: 2, PageSize: 1},
+		{RowGroupSize: 3, Dictionary: true, NoStatistics: true},
+	}
+
+	for _, options := range tests {
+		name := fmt.Sprintf("%+v", options)
+		file := openTestFile(t, testRows, options)
+
+		assert.Equal(t, int64(len(testRows)), file.NumRows, name)
+		names := make([]string, 0, len(file.Columns))
+		for _, column := range file.Columns {
+			names = append(names, column.Name)
+		}
+		assert.Equal(t, []string{"id", "name", "score", "active", "born", "level"}, names, name)
+
+		rows, err := readAll(file)
+		assert.NoError(t, err, name)
+		assert.Equal(t, testValues, rows, name)
+	}
+}
+
+func TestRowGroup_Stats(t *testing.T) {
+	file := openTestFile(t, testRows, parquettest.Options{RowGroupSize: 2})
+	assert.Len(t, file.RowGroups, 3)
+
+	group := &file.RowGroups[0]
+	assert.Equal(t, Stats{Min: "1", Max: "2", HasMinMax: true, HasNullCount: true}, group.Stats(0))
+	assert.Equal(t, Stats{Min: "Alice", Max: "Alice", HasMinMax: true, NullCount: 1, HasNullCount: true}, group.Stats(1))
+	assert.Equal(t, Stats{Min: "false", Max: "true", HasMinMax: true, HasNullCount: true}, group.Stats(3))
+	assert.Equal(t, Stats{Min: "2022-01-01", Max: "2022-01-01", HasMinMax: true, NullCount: 1, HasNullCount: true}, group.Stats(4))
+
+	group = &file.RowGroups[1]
+	assert.Equal(t, Stats{Min: "-1.25", Max: "10", HasMinMax: true, HasNullCount: true}, group.Stats(2))
+	assert.Equal(t, Stats{Min: "1969-12-31", Max: "1970-01-01", HasMinMax: true, HasNullCount: true}, group.Stats(4))
+
+	group = &file.RowGroups[2]
+	assert.Equal(t, Stats{NullCount: 1, HasNullCount: true}, group.Stats(4))
+
+	file = openTestFile(t, testRows, parquettest.Options{NoStatistics: true})
+	assert.Equal(t, Stats{}, file.RowGroups[0].Stats(0))
+}
+
+func TestOpen_errors(t *testing.T) {
+	data, err := parquettest.Write(testColumns, testRows, parquettest.Options{})
+	assert.NoError(t, err)
+
+	tests := map[string][]byte{
+		"empty":            nil,
+		"csv":              []byte("id,name\n1,Alice\n2,Bob\n"),
+		"no footer magic":  data[:len(data)-1],
+		"long metadata":    append(append([]byte{}, data[:len(data)-8]...), 0xFF, 0xFF, 0, 0, 'P', 'A', 'R', '1'),
+		"truncated footer": append(append([]byte{}, data[:len(data)-20]...), data[len(data)-8:]...),
+	}
+
+	for name, data := range tests {
+		_, err := Open(bytes.NewReader(data), int64(len(data)))
+		assert.ErrorIs(t, err, ErrInvalidFile, name)
+	}
+
+	encrypted := append(append([]byte{}, data[:len(data)-4]...), "PARE"...)
+	_, err = Open(bytes.NewReader(encrypted), int64(len(encrypted)))
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestColumnReader_errors(t *testing.T) {
+	data, err := parquettest.Write(testColumns, testRows, parquettest.Options{Codec: parquettest.Snappy})
+	assert.NoError(t, err)
+
+	// corrupt the pages but keep the footer
+	corrupted := append([]byte{}, data...)
+	for i := 4; i < 40; i++ {
+		corrupted[i] = 0xFF
+	}
+
+	file, err := Open(bytes.NewReader(corrupted), int64(len(corrupted)))
+	assert.NoError(t, err)
+	_, err = readAll(file)
+	assert.ErrorIs(t, err, ErrInvalidFile)
+
+	reader := &ColumnReader{column: &file.Columns[0], codec: 5}
+	_, err = reader.decompress([]byte{1}, 1)
+	if assert.ErrorIs(t, err, ErrUnsupported) {
+		assert.Contains(t, err.Error(), "LZ4")
+	}
+
+	_, err = reader.decode(5, []byte{0x08, 0x01, 0x05, 0x02, 0x02, 0x00}, 5)
+	if assert.ErrorIs(t, err, ErrUnsupported) {
+		assert.Contains(t, err.Error(), "DELTA_BINARY_PACKED encoding of column id")
+	}
+}
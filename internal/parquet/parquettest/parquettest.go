@@ -0,0 +1,500 @@
+// Package parquettest writes small Parquet files for tests of Parquet readers.
+package parquettest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Type is the type of column values.
+type Type int
+
+// Column types, values of rows are bool, int32, int64, float64 and string,
+// DATE values are int32 days since the Unix epoch.
+const (
+	Boolean Type = iota
+	Int32
+	Int64
+	Double
+	String
+	Date
+)
+
+// Codec is the compression codec of pages.
+type Codec int32
+
+// Compression codecs.
+const (
+	Uncompressed Codec = 0
+	Snappy       Codec = 1
+	Gzip         Codec = 2
+	Zstd         Codec = 6
+)
+
+// Column describes a column of the written file.
+type Column struct {
+	Name string
+	Type Type
+	// Optional columns may have nil values.
+	Optional bool
+}
+
+// Options describe how the file is written.
+type Options struct {
+	Codec Codec
+	// RowGroupSize is the number of rows of a row group, all rows are in one row group by default.
+	RowGroupSize int
+	// PageSize is the number of values of a data page, all values of a chunk are in one page by default.
+	PageSize int
+	// Dictionary encodes values with a dictionary page.
+	Dictionary bool
+	// PageV2 writes version 2 data pages.
+	PageV2 bool
+	// NoStatistics omits statistics of column chunks.
+	NoStatistics bool
+}
+
+// Physical types, converted types and encodings of the format.
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	convertedUTF8 = 0
+	convertedDate = 6
+
+	encodingPlain         = 0
+	encodingRLE           = 3
+	encodingRLEDictionary = 8
+
+	pageData       = 0
+	pageDictionary = 2
+	pageDataV2     = 3
+)
+
+// Write returns the Parquet file with the rows, values of a row are in the column order.
+func Write(columns []Column, rows [][]interface{}, options Options) ([]byte, error) {
+	for _, row := range rows {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("row %v has %d values, %d columns are given", row, len(row), len(columns))
+		}
+	}
+
+	groupSize := options.RowGroupSize
+	if groupSize <= 0 {
+		groupSize = max(len(rows), 1)
+	}
+
+	file := bytes.NewBufferString("PAR1")
+	var groups []*thriftWriter
+
+	for start := 0; start < len(rows) || start == 0; start += groupSize {
+		end := min(start+groupSize, len(rows))
+
+		group := newThriftWriter()
+		group.listField(1, thriftStruct, len(columns))
+		var size int64
+		for i, column := range columns {
+			values := make([]interface{}, 0, end-start)
+			for _, row := range rows[start:end] {
+				values = append(values, row[i])
+			}
+
+			chunk, err := writeChunk(file, column, values, options)
+			if err != nil {
+				return nil, err
+			}
+			size += chunk.size
+			group.beginStruct()
+			group.i64Field(2, chunk.offset)
+			group.structField(3, chunk.meta)
+			group.endStruct()
+		}
+		group.i64Field(2, size)
+		group.i64Field(3, int64(end-start))
+		groups = append(groups, group)
+
+		if end == len(rows) {
+			break
+		}
+	}
+
+	meta := newThriftWriter()
+	meta.i32Field(1, 1)
+	meta.listField(2, thriftStruct, len(columns)+1)
+	meta.beginStruct()
+	meta.binaryField(4, []byte("schema"))
+	meta.i32Field(5, int32(len(columns)))
+	meta.endStruct()
+	for _, column := range columns {
+		meta.beginStruct()
+		meta.i32Field(1, column.physicalType())
+		repetition := int32(0)
+		if column.Optional {
+			repetition = 1
+		}
+		meta.i32Field(3, repetition)
+		meta.binaryField(4, []byte(column.Name))
+		switch column.Type {
+		case String:
+			meta.i32Field(6, convertedUTF8)
+		case Date:
+			meta.i32Field(6, convertedDate)
+		}
+		meta.endStruct()
+	}
+	meta.i64Field(3, int64(len(rows)))
+	meta.listField(4, thriftStruct, len(groups))
+	for _, group := range groups {
+		meta.buf.Write(group.bytes())
+	}
+
+	footer := meta.bytes()
+	file.Write(footer)
+	_ = binary.Write(file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString("PAR1")
+
+	return file.Bytes(), nil
+}
+
+// chunk is a written column chunk.
+type chunk struct {
+	offset int64
+	size   int64
+	meta   *thriftWriter
+}
+
+func writeChunk(file *bytes.Buffer, column Column, values []interface{}, options Options) (chunk, error) {
+	offset := int64(file.Len())
+	dictionaryOffset := int64(0)
+	var uncompressed int64
+
+	var dictionary []interface{}
+	indexes := make(map[interface{}]uint32)
+	if options.Dictionary {
+		for _, value := range values {
+			if _, ok := indexes[value]; value != nil && !ok {
+				indexes[value] = uint32(len(dictionary))
+				dictionary = append(dictionary, value)
+			}
+		}
+
+		plain, err := column.encodePlain(dictionary)
+		if err != nil {
+			return chunk{}, err
+		}
+
+		header := func(w *thriftWriter) {
+			w.beginStructField(7)
+			w.i32Field(1, int32(len(dictionary)))
+			w.i32Field(2, encodingPlain)
+			w.endStruct()
+		}
+		n, err := writePage(file, pageDictionary, header, nil, plain, options.Codec)
+		if err != nil {
+			return chunk{}, err
+		}
+		uncompressed += n
+		dictionaryOffset = offset
+	}
+
+	dataOffset := int64(file.Len())
+	pageSize := options.PageSize
+	if pageSize <= 0 {
+		pageSize = max(len(values), 1)
+	}
+
+	for start := 0; start < len(values) || start == 0; start += pageSize {
+		page := values[start:min(start+pageSize, len(values))]
+
+		var levels []byte
+		present := make([]interface{}, 0, len(page))
+		if column.Optional {
+			defs := make([]uint32, len(page))
+			for i, value := range page {
+				if value != nil {
+					defs[i] = 1
+				}
+			}
+			levels = encodeHybrid(defs, 1)
+		}
+		for _, value := range page {
+			if value != nil {
+				present = append(present, value)
+			} else if !column.Optional {
+				return chunk{}, fmt.Errorf("null value of required column %s", column.Name)
+			}
+		}
+
+		var data []byte
+		encoding := int32(encodingPlain)
+		if options.Dictionary {
+			encoding = encodingRLEDictionary
+			ids := make([]uint32, len(present))
+			for i, value := range present {
+				ids[i] = indexes[value]
+			}
+			width := bits.Len(uint(max(len(dictionary)-1, 0)))
+			data = append([]byte{byte(width)}, encodeHybrid(ids, width)...)
+		} else {
+			var err error
+			data, err = column.encodePlain(present)
+			if err != nil {
+				return chunk{}, err
+			}
+		}
+
+		typ := int32(pageData)
+		nulls := len(page) - len(present)
+		defLength := len(levels)
+		header := func(w *thriftWriter) {
+			w.beginStructField(5)
+			w.i32Field(1, int32(len(page)))
+			w.i32Field(2, encoding)
+			w.i32Field(3, encodingRLE)
+			w.i32Field(4, encodingRLE)
+			w.endStruct()
+		}
+		if options.PageV2 {
+			typ = pageDataV2
+			header = func(w *thriftWriter) {
+				w.beginStructField(8)
+				w.i32Field(1, int32(len(page)))
+				w.i32Field(2, int32(nulls))
+				w.i32Field(3, int32(len(page)))
+				w.i32Field(4, encoding)
+				w.i32Field(5, int32(defLength))
+				w.i32Field(6, 0)
+				w.endStruct()
+			}
+		} else if levels != nil {
+			prefixed := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+			data = append(append(prefixed, levels...), data...)
+			levels = nil
+		}
+
+		n, err := writePage(file, typ, header, levels, data, options.Codec)
+		if err != nil {
+			return chunk{}, err
+		}
+		uncompressed += n
+
+		if start+pageSize >= len(values) {
+			break
+		}
+	}
+
+	meta := newThriftWriter()
+	meta.i32Field(1, column.physicalType())
+	meta.listField(2, thriftI32, 1)
+	meta.writeVarint(zigzag(encodingPlain))
+	meta.listField(3, thriftBinary, 1)
+	meta.writeBinary([]byte(column.Name))
+	meta.i32Field(4, int32(options.Codec))
+	meta.i64Field(5, int64(len(values)))
+	meta.i64Field(6, uncompressed)
+	meta.i64Field(7, int64(file.Len())-offset)
+	meta.i64Field(9, dataOffset)
+	if dictionaryOffset > 0 {
+		meta.i64Field(11, dictionaryOffset)
+	}
+	if !options.NoStatistics {
+		stats, err := column.statistics(values)
+		if err != nil {
+			return chunk{}, err
+		}
+		meta.structField(12, stats)
+	}
+
+	return chunk{offset: offset, size: int64(file.Len()) - offset, meta: meta}, nil
+}
+
+// writePage writes the page header and the page, header writes the header of the page type.
+// Levels of version 2 pages aren't compressed. It returns the uncompressed size of the page with its header.
+func writePage(file *bytes.Buffer, typ int32, header func(*thriftWriter), levels, data []byte, codec Codec) (int64, error) {
+	compressed, err := compress(data, codec)
+	if err != nil {
+		return 0, err
+	}
+
+	page := newThriftWriter()
+	page.i32Field(1, typ)
+	page.i32Field(2, int32(len(levels)+len(data)))
+	page.i32Field(3, int32(len(levels)+len(compressed)))
+	header(page)
+	encoded := page.bytes()
+
+	file.Write(encoded)
+	file.Write(levels)
+	file.Write(compressed)
+
+	return int64(len(encoded) + len(levels) + len(data)), nil
+}
+
+func compress(data []byte, codec Codec) ([]byte, error) {
+	switch codec {
+	case Uncompressed:
+		return data, nil
+	case Snappy:
+		return snappy.Encode(nil, data), nil
+	case Gzip:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		_, _ = writer.Write(data)
+		err := writer.Close()
+		return buf.Bytes(), err
+	case Zstd:
+		encoder, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer encoder.Close()
+		return encoder.EncodeAll(data, nil), nil
+	}
+
+	return nil, fmt.Errorf("unknown codec %d", codec)
+}
+
+func (c Column) physicalType() int32 {
+	switch c.Type {
+	case Boolean:
+		return typeBoolean
+	case Int32, Date:
+		return typeInt32
+	case Int64:
+		return typeInt64
+	case Double:
+		return typeDouble
+	}
+
+	return typeByteArray
+}
+
+// encodePlain encodes values with the plain encoding.
+func (c Column) encodePlain(values []interface{}) ([]byte, error) {
+	var buf []byte
+	if c.Type == Boolean {
+		buf = make([]byte, (len(values)+7)/8)
+	}
+
+	for i, value := range values {
+		encoded, err := c.encodeValue(value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch c.Type {
+		case Boolean:
+			buf[i/8] |= encoded[0] << (i % 8)
+		case String:
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(encoded)))
+			buf = append(buf, encoded...)
+		default:
+			buf = append(buf, encoded...)
+		}
+	}
+
+	return buf, nil
+}
+
+// encodeValue encodes the value as in statistics: plain encoded without the length of byte arrays.
+func (c Column) encodeValue(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case bool:
+		if c.Type == Boolean {
+			if v {
+				return []byte{1}, nil
+			}
+			return []byte{0}, nil
+		}
+	case int32:
+		if c.Type == Int32 || c.Type == Date {
+			return binary.LittleEndian.AppendUint32(nil, uint32(v)), nil
+		}
+	case int64:
+		if c.Type == Int64 {
+			return binary.LittleEndian.AppendUint64(nil, uint64(v)), nil
+		}
+	case float64:
+		if c.Type == Double {
+			return binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)), nil
+		}
+	case string:
+		if c.Type == String {
+			return []byte(v), nil
+		}
+	}
+
+	return nil, fmt.Errorf("value %v (%T) of column %s", value, value, c.Name)
+}
+
+// statistics returns the statistics of the values.
+func (c Column) statistics(values []interface{}) (*thriftWriter, error) {
+	present := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			present = append(present, value)
+		}
+	}
+
+	sort.Slice(present, func(i, j int) bool {
+		switch a := present[i].(type) {
+		case bool:
+			return !a && present[j].(bool)
+		case int32:
+			return a < present[j].(int32)
+		case int64:
+			return a < present[j].(int64)
+		case float64:
+			return a < present[j].(float64)
+		case string:
+			return a < present[j].(string)
+		}
+		return false
+	})
+
+	stats := newThriftWriter()
+	stats.i64Field(3, int64(len(values)-len(present)))
+	if len(present) > 0 {
+		maxValue, err := c.encodeValue(present[len(present)-1])
+		if err != nil {
+			return nil, err
+		}
+		minValue, err := c.encodeValue(present[0])
+		if err != nil {
+			return nil, err
+		}
+		stats.binaryField(5, maxValue)
+		stats.binaryField(6, minValue)
+	}
+
+	return stats, nil
+}
+
+// encodeHybrid encodes the values as one bit-packed run of the RLE and bit-packing hybrid encoding.
+func encodeHybrid(values []uint32, width int) []byte {
+	groups := (len(values) + 7) / 8
+	buf := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+
+	packed := make([]byte, groups*width)
+	for i, value := range values {
+		for bit := 0; bit < width; bit++ {
+			if value>>bit&1 == 1 {
+				pos := i*width + bit
+				packed[pos/8] |= 1 << (pos % 8)
+			}
+		}
+	}
+
+	return append(buf, packed...)
+}
@@ -0,0 +1,108 @@
+package parquettest
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Types of the Thrift compact protocol.
+const (
+	thriftStop   = 0
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter writes fields of Thrift structs with the compact protocol.
+// Nested structs are started with beginStruct and finished with endStruct.
+type thriftWriter struct {
+	buf bytes.Buffer
+	// last is the id of the last written field of the current struct, stack keeps the ids of outer structs.
+	last  int16
+	stack []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{}
+}
+
+// bytes returns the written fields terminated as a struct.
+func (w *thriftWriter) bytes() []byte {
+	return append(w.buf.Bytes(), thriftStop)
+}
+
+func (w *thriftWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - w.last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.writeVarint(zigzag(int64(id)))
+	}
+	w.last = id
+}
+
+func (w *thriftWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.writeVarint(zigzag(int64(v)))
+}
+
+func (w *thriftWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.writeVarint(zigzag(v))
+}
+
+func (w *thriftWriter) binaryField(id int16, v []byte) {
+	w.fieldHeader(id, thriftBinary)
+	w.writeBinary(v)
+}
+
+// listField writes the header of a list of size elements, the elements are written next.
+func (w *thriftWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, thriftList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+
+	w.buf.WriteByte(0xF0 | elemType)
+	w.writeVarint(uint64(size))
+}
+
+// structField writes the struct written by the other writer.
+func (w *thriftWriter) structField(id int16, v *thriftWriter) {
+	w.fieldHeader(id, thriftStruct)
+	w.buf.Write(v.bytes())
+}
+
+// beginStructField starts a struct field, its fields are written until endStruct.
+func (w *thriftWriter) beginStructField(id int16) {
+	w.fieldHeader(id, thriftStruct)
+	w.beginStruct()
+}
+
+// beginStruct starts a struct which is a list element.
+func (w *thriftWriter) beginStruct() {
+	w.stack = append(w.stack, w.last)
+	w.last = 0
+}
+
+func (w *thriftWriter) endStruct() {
+	w.buf.WriteByte(thriftStop)
+	w.last = w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+}
+
+func (w *thriftWriter) writeBinary(v []byte) {
+	w.writeVarint(uint64(len(v)))
+	w.buf.Write(v)
+}
+
+func (w *thriftWriter) writeVarint(v uint64) {
+	w.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
@@ -0,0 +1,218 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Types of the Thrift compact protocol which the Parquet metadata is written with.
+const (
+	thriftStop   = 0
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftDouble = 7
+	thriftBinary = 8
+	thriftList   = 9
+	thriftSet    = 10
+	thriftMap    = 11
+	thriftStruct = 12
+)
+
+// maxThriftBinary limits strings and binary values of the metadata.
+const maxThriftBinary = 64 << 20
+
+// maxThriftDepth limits nesting of skipped structures.
+const maxThriftDepth = 64
+
+// byteReader is read by the Thrift decoder.
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// thriftReader decodes values of the Thrift compact protocol.
+type thriftReader struct {
+	r     byteReader
+	depth int
+}
+
+func (t *thriftReader) readVarint() (uint64, error) {
+	v, err := binary.ReadUvarint(t.r)
+	return v, unexpectedEOF(err)
+}
+
+func (t *thriftReader) readInt() (int64, error) {
+	v, err := t.readVarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (t *thriftReader) readI32() (int32, error) {
+	v, err := t.readInt()
+	return int32(v), err
+}
+
+func (t *thriftReader) readBinary() ([]byte, error) {
+	size, err := t.readVarint()
+	if err != nil {
+		return nil, err
+	}
+
+	if size > maxThriftBinary {
+		return nil, fmt.Errorf("%w: metadata value of %d bytes", ErrInvalidFile, size)
+	}
+
+	buf := make([]byte, size)
+	_, err = io.ReadFull(t.r, buf)
+	return buf, unexpectedEOF(err)
+}
+
+func (t *thriftReader) readString() (string, error) {
+	buf, err := t.readBinary()
+	return string(buf), err
+}
+
+// readStruct calls field for every field of the struct, field must read or skip the value.
+// The value of a bool field is its type.
+func (t *thriftReader) readStruct(field func(id int16, typ byte) error) error {
+	var id int16
+	for {
+		header, err := t.r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+
+		typ := header & 0x0F
+		if typ == thriftStop {
+			return nil
+		}
+
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			v, err := t.readInt()
+			if err != nil {
+				return err
+			}
+			id = int16(v)
+		}
+
+		err = field(id, typ)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readList calls elem for every element of the list, elem must read or skip the value.
+func (t *thriftReader) readList(elem func(typ byte) error) error {
+	header, err := t.r.ReadByte()
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+
+	typ := header & 0x0F
+	size := uint64(header >> 4)
+	if size == 15 {
+		size, err = t.readVarint()
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := uint64(0); i < size; i++ {
+		err = elem(typ)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// skip reads the value of the type.
+func (t *thriftReader) skip(typ byte) error {
+	switch typ {
+	case thriftTrue, thriftFalse:
+		return nil
+	case thriftByte:
+		_, err := t.r.ReadByte()
+		return unexpectedEOF(err)
+	case thriftI16, thriftI32, thriftI64:
+		_, err := t.readVarint()
+		return err
+	case thriftDouble:
+		_, err := io.ReadFull(t.r, make([]byte, 8))
+		return unexpectedEOF(err)
+	case thriftBinary:
+		_, err := t.readBinary()
+		return err
+	}
+
+	t.depth++
+	defer func() { t.depth-- }()
+	if t.depth > maxThriftDepth {
+		return fmt.Errorf("%w: metadata is nested too deep", ErrInvalidFile)
+	}
+
+	switch typ {
+	case thriftList, thriftSet:
+		return t.readList(t.skipElement)
+	case thriftMap:
+		return t.skipMap()
+	case thriftStruct:
+		return t.readStruct(func(_ int16, typ byte) error {
+			return t.skip(typ)
+		})
+	}
+
+	return fmt.Errorf("%w: unknown metadata type %d", ErrInvalidFile, typ)
+}
+
+// skipElement reads an element of a list, bool elements take a byte unlike bool fields.
+func (t *thriftReader) skipElement(typ byte) error {
+	if typ == thriftTrue || typ == thriftFalse {
+		return t.skip(thriftByte)
+	}
+
+	return t.skip(typ)
+}
+
+func (t *thriftReader) skipMap() error {
+	size, err := t.readVarint()
+	if err != nil || size == 0 {
+		return err
+	}
+
+	types, err := t.r.ReadByte()
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+
+	for i := uint64(0); i < size; i++ {
+		err = t.skipElement(types >> 4)
+		if err != nil {
+			return err
+		}
+
+		err = t.skipElement(types & 0x0F)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unexpectedEOF reports the end of data in the middle of a value as a malformed file.
+func unexpectedEOF(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: unexpected end of metadata", ErrInvalidFile)
+	}
+
+	return err
+}
@@ -0,0 +1,211 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts of formatted dates, times and timestamps, fractions of seconds are written only if they aren't zero.
+const (
+	dateLayout      = "2006-01-02"
+	timeLayout      = "15:04:05.999999999"
+	timestampLayout = "2006-01-02 15:04:05.999999999"
+)
+
+// julianUnixEpoch is the Julian day of 1970-01-01, INT96 timestamps count days from the Julian epoch.
+const julianUnixEpoch = 2440588
+
+const secondsPerDay = 24 * 60 * 60
+
+func (c *Column) isDate() bool {
+	e := c.element
+	return e.logical.kind == logicalDate || e.hasConverted && e.convertedType == convertedDate
+}
+
+func (c *Column) isTime() bool {
+	e := c.element
+	return e.logical.kind == logicalTime || e.hasConverted && (e.convertedType == convertedTimeMillis || e.convertedType == convertedTimeMicros)
+}
+
+func (c *Column) isDecimal() bool {
+	e := c.element
+	return e.logical.kind == logicalDecimal || e.hasConverted && e.convertedType == convertedDecimal
+}
+
+func (c *Column) isUnsigned() bool {
+	e := c.element
+	if e.logical.kind == logicalInteger {
+		return !e.logical.signed
+	}
+
+	return e.hasConverted && e.convertedType >= convertedUint8 && e.convertedType <= convertedUint64
+}
+
+// scale returns the number of digits after the decimal point of a decimal column.
+func (c *Column) scale() int {
+	if c.element.logical.kind == logicalDecimal {
+		return int(c.element.logical.scale)
+	}
+
+	return int(c.element.scale)
+}
+
+// timeUnit returns the unit of a time column.
+func (c *Column) timeUnit() int16 {
+	e := c.element
+	switch {
+	case e.logical.kind == logicalTime:
+		return e.logical.unit
+	case e.hasConverted && e.convertedType == convertedTimeMillis:
+		return unitMillis
+	case e.hasConverted && e.convertedType == convertedTimeMicros:
+		return unitMicros
+	}
+
+	return 0
+}
+
+// timestampUnit returns the unit of a timestamp column or zero for other columns.
+func (c *Column) timestampUnit() int16 {
+	e := c.element
+	switch {
+	case e.logical.kind == logicalTimestamp:
+		return e.logical.unit
+	case e.hasConverted && e.convertedType == convertedTimestampMillis:
+		return unitMillis
+	case e.hasConverted && e.convertedType == convertedTimestampMicros:
+		return unitMicros
+	}
+
+	return 0
+}
+
+func formatBoolean(v bool) string {
+	return strconv.FormatBool(v)
+}
+
+func (c *Column) formatInt32(v int32) string {
+	switch {
+	case c.isDate():
+		return time.Unix(int64(v)*secondsPerDay, 0).UTC().Format(dateLayout)
+	case c.isDecimal():
+		return formatDecimal(big.NewInt(int64(v)), c.scale())
+	case c.isTime():
+		return formatTime(int64(v), c.timeUnit())
+	case c.isUnsigned():
+		return strconv.FormatUint(uint64(uint32(v)), 10)
+	}
+
+	return strconv.FormatInt(int64(v), 10)
+}
+
+func (c *Column) formatInt64(v int64) string {
+	switch {
+	case c.timestampUnit() != 0:
+		return unitTime(v, c.timestampUnit()).UTC().Format(timestampLayout)
+	case c.isDecimal():
+		return formatDecimal(big.NewInt(v), c.scale())
+	case c.isTime():
+		return formatTime(v, c.timeUnit())
+	case c.isUnsigned():
+		return strconv.FormatUint(uint64(v), 10)
+	}
+
+	return strconv.FormatInt(v, 10)
+}
+
+// formatInt96 formats a legacy timestamp: nanoseconds of the day and the Julian day.
+func formatInt96(v []byte) string {
+	nanos := int64(binary.LittleEndian.Uint64(v))
+	days := int64(binary.LittleEndian.Uint32(v[8:])) - julianUnixEpoch
+
+	return time.Unix(days*secondsPerDay, nanos).UTC().Format(timestampLayout)
+}
+
+func formatFloat(v float64, bitSize int) string {
+	return strconv.FormatFloat(v, 'g', -1, bitSize)
+}
+
+func (c *Column) formatBytes(v []byte) string {
+	e := c.element
+	switch {
+	case c.isDecimal():
+		return formatDecimal(bigEndianInt(v), c.scale())
+	case e.logical.kind == logicalUUID && len(v) == 16:
+		s := hex.EncodeToString(v)
+		return strings.Join([]string{s[:8], s[8:12], s[12:16], s[16:20], s[20:]}, "-")
+	case e.logical.kind == logicalFloat16 && len(v) == 2:
+		return formatFloat(float64(float16(binary.LittleEndian.Uint16(v))), 32)
+	}
+
+	return string(v)
+}
+
+// unitTime returns the time of the value in the unit since the Unix epoch.
+func unitTime(v int64, unit int16) time.Time {
+	switch unit {
+	case unitMillis:
+		return time.UnixMilli(v)
+	case unitMicros:
+		return time.UnixMicro(v)
+	}
+
+	return time.Unix(0, v)
+}
+
+// formatTime formats the time of the day in the unit since midnight.
+func formatTime(v int64, unit int16) string {
+	return unitTime(v, unit).UTC().Format(timeLayout)
+}
+
+// formatDecimal formats the unscaled value with scale digits after the decimal point.
+func formatDecimal(unscaled *big.Int, scale int) string {
+	digits := new(big.Int).Abs(unscaled).String()
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+
+	if unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// bigEndianInt decodes a big-endian two's complement integer.
+func bigEndianInt(v []byte) *big.Int {
+	n := new(big.Int).SetBytes(v)
+	if len(v) > 0 && v[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(v))*8))
+	}
+
+	return n
+}
+
+// float16 converts a half precision float.
+func float16(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1F
+	frac := uint32(h) & 0x3FF
+
+	switch exp {
+	case 0:
+		v := float32(frac) / (1 << 24)
+		if sign != 0 {
+			return -v
+		}
+		return v
+	case 0x1F:
+		return math.Float32frombits(sign | 0xFF<<23 | frac<<13)
+	}
+
+	return math.Float32frombits(sign | (exp+127-15)<<23 | frac<<13)
+}
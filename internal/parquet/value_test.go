@@ -0,0 +1,158 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumn_formatFixed(t *testing.T) {
+	int32Value := func(v int32) []byte {
+		return binary.LittleEndian.AppendUint32(nil, uint32(v))
+	}
+	int64Value := func(v int64) []byte {
+		return binary.LittleEndian.AppendUint64(nil, uint64(v))
+	}
+
+	tests := []struct {
+		name    string
+		element schemaElement
+		value   []byte
+		want    string
+	}{
+		{
+			name:    "int32",
+			element: schemaElement{typ: typeInt32},
+			value:   int32Value(-42),
+			want:    "-42",
+		},
+		{
+			name:    "uint32",
+			element: schemaElement{typ: typeInt32, hasConverted: true, convertedType: convertedUint32},
+			value:   int32Value(-1),
+			want:    "4294967295",
+		},
+		{
+			name:    "unsigned logical int64",
+			element: schemaElement{typ: typeInt64, logical: logicalType{kind: logicalInteger, bitWidth: 64}},
+			value:   int64Value(-1),
+			want:    "18446744073709551615",
+		},
+		{
+			name:    "date",
+			element: schemaElement{typ: typeInt32, hasConverted: true, convertedType: convertedDate},
+			value:   int32Value(18993),
+			want:    "2022-01-01",
+		},
+		{
+			name:    "date before the epoch",
+			element: schemaElement{typ: typeInt32, logical: logicalType{kind: logicalDate}},
+			value:   int32Value(-1),
+			want:    "1969-12-31",
+		},
+		{
+			name:    "int32 decimal",
+			element: schemaElement{typ: typeInt32, hasConverted: true, convertedType: convertedDecimal, scale: 2},
+			value:   int32Value(-5),
+			want:    "-0.05",
+		},
+		{
+			name:    "int64 decimal",
+			element: schemaElement{typ: typeInt64, logical: logicalType{kind: logicalDecimal, scale: 3}},
+			value:   int64Value(1234567),
+			want:    "1234.567",
+		},
+		{
+			name:    "time millis",
+			element: schemaElement{typ: typeInt32, hasConverted: true, convertedType: convertedTimeMillis},
+			value:   int32Value((13*3600+5*60+7)*1000 + 250),
+			want:    "13:05:07.25",
+		},
+		{
+			name:    "timestamp micros",
+			element: schemaElement{typ: typeInt64, logical: logicalType{kind: logicalTimestamp, unit: unitMicros}},
+			value:   int64Value(1640995200000001),
+			want:    "2022-01-01 00:00:00.000001",
+		},
+		{
+			name:    "timestamp nanos",
+			element: schemaElement{typ: typeInt64, logical: logicalType{kind: logicalTimestamp, unit: unitNanos}},
+			value:   int64Value(1640995230000000000),
+			want:    "2022-01-01 00:00:30",
+		},
+		{
+			name:    "int96 timestamp",
+			element: schemaElement{typ: typeInt96},
+			value:   append(int64Value(3600*1e9), binary.LittleEndian.AppendUint32(nil, 2459581)...),
+			want:    "2022-01-01 01:00:00",
+		},
+		{
+			name:    "float",
+			element: schemaElement{typ: typeFloat},
+			value:   binary.LittleEndian.AppendUint32(nil, math.Float32bits(0.1)),
+			want:    "0.1",
+		},
+		{
+			name:    "double",
+			element: schemaElement{typ: typeDouble},
+			value:   binary.LittleEndian.AppendUint64(nil, math.Float64bits(-2.5e-10)),
+			want:    "-2.5e-10",
+		},
+		{
+			name:    "fixed length decimal",
+			element: schemaElement{typ: typeFixedLenByteArray, typeLength: 3, logical: logicalType{kind: logicalDecimal, scale: 1}},
+			value:   []byte{0xFF, 0xFF, 0x85},
+			want:    "-12.3",
+		},
+		{
+			name:    "uuid",
+			element: schemaElement{typ: typeFixedLenByteArray, typeLength: 16, logical: logicalType{kind: logicalUUID}},
+			value: []byte{0x12, 0x3E, 0x45, 0x67, 0xE8, 0x9B, 0x12, 0xD3,
+				0xA4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+			want: "123e4567-e89b-12d3-a456-426614174000",
+		},
+		{
+			name:    "float16",
+			element: schemaElement{typ: typeFixedLenByteArray, typeLength: 2, logical: logicalType{kind: logicalFloat16}},
+			value:   []byte{0x00, 0xC1},
+			want:    "-2.5",
+		},
+		{
+			name:    "fixed length bytes",
+			element: schemaElement{typ: typeFixedLenByteArray, typeLength: 2},
+			value:   []byte("ab"),
+			want:    "ab",
+		},
+	}
+
+	for _, test := range tests {
+		column := &Column{Name: test.name, element: test.element}
+		assert.Equal(t, test.want, column.formatFixed(test.value), test.name)
+	}
+}
+
+func TestColumn_Kind(t *testing.T) {
+	tests := []struct {
+		name    string
+		element schemaElement
+		want    Kind
+	}{
+		{name: "int64", element: schemaElement{typ: typeInt64}, want: KindNumber},
+		{name: "double", element: schemaElement{typ: typeDouble}, want: KindNumber},
+		{name: "decimal", element: schemaElement{typ: typeByteArray, hasConverted: true, convertedType: convertedDecimal}, want: KindNumber},
+		{name: "string", element: schemaElement{typ: typeByteArray, hasConverted: true, convertedType: convertedUTF8}, want: KindText},
+		{name: "boolean", element: schemaElement{typ: typeBoolean}, want: KindText},
+		{name: "date", element: schemaElement{typ: typeInt32, logical: logicalType{kind: logicalDate}}, want: KindText},
+		{name: "time", element: schemaElement{typ: typeInt64, logical: logicalType{kind: logicalTime, unit: unitMicros}}, want: KindOther},
+		{name: "timestamp", element: schemaElement{typ: typeInt64, hasConverted: true, convertedType: convertedTimestampMillis}, want: KindOther},
+		{name: "int96", element: schemaElement{typ: typeInt96}, want: KindOther},
+		{name: "float16", element: schemaElement{typ: typeFixedLenByteArray, typeLength: 2, logical: logicalType{kind: logicalFloat16}}, want: KindOther},
+	}
+
+	for _, test := range tests {
+		column := &Column{Name: test.name, element: test.element}
+		assert.Equal(t, test.want, column.Kind(), test.name)
+	}
+}
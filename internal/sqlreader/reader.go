@@ -7,11 +7,67 @@ import (
 	"errors"
 	"io"
 	"strings"
+	"sync"
+	"unicode"
 )
 
+// StatementTerminator ends a statement.
+const StatementTerminator = ';'
+
+// CommandPrefix starts a monitor command. Commands and exit are accepted without a statement terminator.
+const CommandPrefix = '\\'
+
+const exitCommand = "exit"
+
+// completionSeparators separate the word being completed from the rest of the line.
+const completionSeparators = " \t,()=<>!"
+
+// Completer returns completions of the word being typed.
+// before contains the statement text before the word including the previous lines of the statement,
+// after contains the rest of the line.
+type Completer func(before, word, after string) []string
+
+// lineSource reads lines from the user showing the prompt.
+type lineSource interface {
+	readLine(out io.Writer, prompt string) (string, error)
+	appendHistory(entry string)
+	close() error
+}
+
 // Reader describes reader
 type Reader struct {
-	bufioReader *bufio.Reader
+	source    lineSource
+	requests  chan lineRequest
+	lines     chan readResult
+	pending   bool
+	startOnce sync.Once
+
+	completer Completer
+	// statementHead is the unterminated statement when the current line is requested,
+	// it's used only by the goroutine reading lines.
+	statementHead string
+
+	out                io.Writer
+	prompt             string
+	continuationPrompt string
+
+	statements []string
+	buffer     strings.Builder
+	quote      rune
+	escaped    bool
+	eof        bool
+}
+
+// lineRequest describes a request of the next line.
+type lineRequest struct {
+	prompt        string
+	statementHead string
+}
+
+// readResult describes a line read from the underlying reader.
+type readResult struct {
+	line string
+	err  error
 }
 
 // ErrInterrupted error for canceled context.
@@ -19,27 +75,248 @@ var ErrInterrupted = errors.New("context canceled")
 
 // NewSQLReader returns a new Reader
 func NewSQLReader(rd io.Reader) *Reader {
-	reader := bufio.NewReader(rd)
+	return newReader(&bufioSource{reader: bufio.NewReader(rd)})
+}
+
+func newReader(source lineSource) *Reader {
 	return &Reader{
-		bufioReader: reader,
+		source:   source,
+		requests: make(chan lineRequest, 1),
+		lines:    make(chan readResult),
 	}
 }
 
+// Close closes the line source.
+func (r *Reader) Close() error {
+	return r.source.close()
+}
+
 // ReadLine returns the read string or error.
+// The line isn't lost if the context is canceled, it's returned by the next call.
 func (r *Reader) ReadLine(ctx context.Context) (string, error) {
-	var resultStr string
-	var resultErr error
+	return r.readLine(ctx, "")
+}
 
-	doneCh := make(chan struct{})
-	go func() {
-		defer close(doneCh)
-		resultStr, resultErr = r.bufioReader.ReadString('\n')
-	}()
+func (r *Reader) readLine(ctx context.Context, prompt string) (string, error) {
+	r.startOnce.Do(func() {
+		go r.readLines()
+	})
+
+	if !r.pending {
+		r.pending = true
+		r.requests <- lineRequest{prompt: prompt, statementHead: r.buffer.String()}
+	}
 
 	select {
 	case <-ctx.Done():
 		return "", ErrInterrupted
-	case <-doneCh:
-		return strings.TrimSpace(resultStr), resultErr
+	case result := <-r.lines:
+		r.pending = false
+		return strings.TrimSpace(result.line), result.err
+	}
+}
+
+// readLines reads a line from the source for every request.
+func (r *Reader) readLines() {
+	for request := range r.requests {
+		r.statementHead = request.statementHead
+		line, err := r.source.readLine(r.out, request.prompt)
+		r.lines <- readResult{line: line, err: err}
+	}
+}
+
+// SetPrompt sets prompts which are written to out before reading a new line.
+// The terminal reader always writes prompts to stdout.
+// The continuation prompt is used while a statement isn't terminated yet.
+func (r *Reader) SetPrompt(out io.Writer, prompt, continuationPrompt string) {
+	r.out = out
+	r.prompt = prompt
+	r.continuationPrompt = continuationPrompt
+}
+
+// SetCompleter sets the function completing words, only the terminal reader uses it.
+// It must be set before reading the first line.
+func (r *Reader) SetCompleter(completer Completer) {
+	r.completer = completer
+}
+
+// completeWord completes the word before the cursor position given in runes.
+func (r *Reader) completeWord(line string, pos int) (head string, completions []string, tail string) {
+	if r.completer == nil {
+		return line, nil, ""
+	}
+
+	runes := []rune(line)
+	beforeCursor := string(runes[:pos])
+	tail = string(runes[pos:])
+
+	head = beforeCursor[:strings.LastIndexAny(beforeCursor, completionSeparators)+1]
+	word := beforeCursor[len(head):]
+
+	before := head
+	if pos := strings.LastIndexByte(head, StatementTerminator); pos != -1 {
+		before = head[pos+1:]
+	} else if r.statementHead != "" {
+		before = r.statementHead + " " + head
+	}
+
+	return head, r.completer(before, word, tail), tail
+}
+
+// ReadStatement returns the next statement terminated by a semicolon outside of string literals.
+// A statement may span several lines and a line may contain several statements.
+// The unterminated rest of the input is returned as the last statement when the input ends.
+func (r *Reader) ReadStatement(ctx context.Context) (string, error) {
+	for len(r.statements) == 0 {
+		if r.eof {
+			return "", io.EOF
+		}
+
+		line, err := r.readLine(ctx, r.currentPrompt())
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+
+		if err == io.EOF {
+			r.eof = true
+			r.splitLine(line)
+			r.flush()
+			continue
+		}
+
+		if r.buffer.Len() == 0 {
+			if command, rest, ok := splitCommand(line); ok {
+				r.source.appendHistory(command)
+				r.splitLine(rest)
+				return command, nil
+			}
+		}
+
+		r.splitLine(line)
+	}
+
+	statement := r.statements[0]
+	r.statements = r.statements[1:]
+	if !r.eof {
+		r.source.appendHistory(statement + string(StatementTerminator))
+	}
+
+	return statement, nil
+}
+
+// Reset discards the unterminated statement and the read statements which aren't returned yet.
+func (r *Reader) Reset() {
+	r.buffer.Reset()
+	r.statements = nil
+	r.quote = 0
+	r.escaped = false
+}
+
+// IsEmpty returns true if there is no unterminated statement or statements which aren't returned yet.
+func (r *Reader) IsEmpty() bool {
+	return r.buffer.Len() == 0 && len(r.statements) == 0
+}
+
+// splitCommand returns the command the line starts with and the rest of the line after it.
+// A command ends at the first terminator outside of quotes, the rest is read as statements.
+func splitCommand(line string) (command, rest string, ok bool) {
+	end := len(line)
+	var quote rune
+	for i, char := range line {
+		if quote != 0 {
+			if char == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		if char == '\'' || char == '"' {
+			quote = char
+		} else if char == StatementTerminator {
+			end = i
+			break
+		}
+	}
+
+	command = strings.TrimSpace(line[:end])
+	if !strings.HasPrefix(command, string(CommandPrefix)) && !strings.EqualFold(command, exitCommand) {
+		return "", "", false
+	}
+
+	if end < len(line) {
+		rest = line[end+1:]
+	}
+
+	return command, rest, true
+}
+
+func (r *Reader) currentPrompt() string {
+	if r.buffer.Len() > 0 {
+		return r.continuationPrompt
+	}
+
+	return r.prompt
+}
+
+// splitLine appends the line to the current statement and collects the terminated statements.
+func (r *Reader) splitLine(line string) {
+	if line == "" {
+		return
 	}
+
+	if r.buffer.Len() > 0 {
+		r.buffer.WriteByte(' ')
+	}
+
+	for _, char := range line {
+		switch {
+		case r.buffer.Len() == 0 && unicode.IsSpace(char):
+			continue
+		case r.escaped:
+			r.escaped = false
+		case r.quote != 0 && char == '\\':
+			r.escaped = true
+		case r.quote != 0 && char == r.quote:
+			r.quote = 0
+		case r.quote != 0:
+		case char == '\'' || char == '"':
+			r.quote = char
+		case char == StatementTerminator:
+			r.flush()
+			continue
+		}
+
+		r.buffer.WriteRune(char)
+	}
+}
+
+// flush moves the current statement to the list of read statements.
+func (r *Reader) flush() {
+	statement := strings.TrimSpace(r.buffer.String())
+	r.buffer.Reset()
+	r.quote = 0
+	r.escaped = false
+
+	if statement != "" {
+		r.statements = append(r.statements, statement)
+	}
+}
+
+// bufioSource reads lines from any reader.
+type bufioSource struct {
+	reader *bufio.Reader
+}
+
+func (s *bufioSource) readLine(out io.Writer, prompt string) (string, error) {
+	if out != nil && prompt != "" {
+		_, _ = io.WriteString(out, prompt)
+	}
+
+	return s.reader.ReadString('\n')
+}
+
+func (s *bufioSource) appendHistory(string) {}
+
+func (s *bufioSource) close() error {
+	return nil
 }
@@ -0,0 +1,217 @@
+package sqlreader
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReader_ReadStatement(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantStatements []string
+	}{
+		{
+			name:           "single line",
+			input:          "select * from users;\n",
+			wantStatements: []string{"select * from users"},
+		},
+		{
+			name:           "multi line",
+			input:          "select *\nfrom users\n  where age > 30;\n",
+			wantStatements: []string{"select * from users where age > 30"},
+		},
+		{
+			name:           "several statements on one line",
+			input:          "select * from users; select name from roles;\n",
+			wantStatements: []string{"select * from users", "select name from roles"},
+		},
+		{
+			name:           "semicolon in string literal",
+			input:          "select * from users where name = 'a;b' or name = \"c\\\";d\";\n",
+			wantStatements: []string{`select * from users where name = 'a;b' or name = "c\";d"`},
+		},
+		{
+			name:           "unterminated statement at the end of input",
+			input:          "select * from users; select *\nfrom roles",
+			wantStatements: []string{"select * from users", "select * from roles"},
+		},
+		{
+			name:           "empty statements",
+			input:          ";;\n\nselect * from users;;\n",
+			wantStatements: []string{"select * from users"},
+		},
+		{
+			name:           "exit without terminator",
+			input:          "exit\nselect * from users;\n",
+			wantStatements: []string{"exit", "select * from users"},
+		},
+		{
+			name:           "command without terminator",
+			input:          "\\format json\nselect * from users;\n",
+			wantStatements: []string{"\\format json", "select * from users"},
+		},
+		{
+			name:           "command followed by statements on the same line",
+			input:          "\\format json; select * from users; select *\nfrom roles;\n",
+			wantStatements: []string{"\\format json", "select * from users", "select * from roles"},
+		},
+		{
+			name:           "terminator quoted in a command",
+			input:          "\\format 'json;' ;exit;\n",
+			wantStatements: []string{"\\format 'json;'", "exit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewSQLReader(strings.NewReader(tt.input))
+
+			var statements []string
+			for {
+				statement, err := reader.ReadStatement(context.Background())
+				if err == io.EOF {
+					break
+				}
+				assert.NoError(t, err)
+				statements = append(statements, statement)
+			}
+
+			assert.Equal(t, tt.wantStatements, statements)
+		})
+	}
+}
+
+func TestReader_SetPrompt(t *testing.T) {
+	reader := NewSQLReader(strings.NewReader("select *\nfrom users; select * from roles;\n"))
+	out := new(bytes.Buffer)
+	reader.SetPrompt(out, "> ", "-> ")
+
+	statement, err := reader.ReadStatement(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "select * from users", statement)
+
+	statement, err = reader.ReadStatement(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "select * from roles", statement)
+
+	assert.Equal(t, "> -> ", out.String())
+}
+
+func TestReader_ReadLineInterrupted(t *testing.T) {
+	input, writer := io.Pipe()
+	reader := NewSQLReader(input)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := reader.ReadLine(ctx)
+	assert.Equal(t, ErrInterrupted, err)
+
+	go func() {
+		_, _ = writer.Write([]byte("select * from users;\n"))
+		_ = writer.Close()
+	}()
+
+	line, err := reader.ReadLine(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "select * from users;", line)
+
+	_, err = reader.ReadLine(context.Background())
+	assert.Equal(t, io.EOF, err)
+	_, err = reader.ReadLine(context.Background())
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReader_Reset(t *testing.T) {
+	reader := NewSQLReader(strings.NewReader("select *\nfrom users; select 1;\nselect * from roles;\n"))
+	assert.True(t, reader.IsEmpty())
+
+	line, err := reader.ReadLine(context.Background())
+	assert.NoError(t, err)
+	reader.splitLine(line)
+	assert.False(t, reader.IsEmpty())
+
+	reader.Reset()
+	assert.True(t, reader.IsEmpty())
+
+	statement, err := reader.ReadStatement(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "from users", statement)
+}
+
+type fakeSource struct {
+	lines   []string
+	prompts []string
+	history []string
+}
+
+func (s *fakeSource) readLine(_ io.Writer, prompt string) (string, error) {
+	s.prompts = append(s.prompts, prompt)
+	if len(s.lines) == 0 {
+		return "", io.EOF
+	}
+
+	line := s.lines[0]
+	s.lines = s.lines[1:]
+	return line, nil
+}
+
+func (s *fakeSource) appendHistory(entry string) {
+	s.history = append(s.history, entry)
+}
+
+func (s *fakeSource) close() error {
+	return nil
+}
+
+func TestReader_History(t *testing.T) {
+	source := &fakeSource{lines: []string{"select *", "from users; select 1", "from roles;", `\format json`, "select"}}
+	reader := newReader(source)
+	reader.SetPrompt(nil, "> ", "-> ")
+
+	for {
+		_, err := reader.ReadStatement(context.Background())
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"select * from users;", "select 1 from roles;", `\format json`}, source.history)
+	assert.Equal(t, []string{"> ", "-> ", "-> ", "> ", "> ", "-> "}, source.prompts)
+	assert.NoError(t, reader.Close())
+}
+
+func TestReader_completeWord(t *testing.T) {
+	reader := newReader(&fakeSource{})
+
+	head, completions, tail := reader.completeWord("sel", 3)
+	assert.Equal(t, "sel", head)
+	assert.Nil(t, completions)
+	assert.Equal(t, "", tail)
+
+	var gotBefore, gotWord, gotAfter string
+	reader.SetCompleter(func(before, word, after string) []string {
+		gotBefore, gotWord, gotAfter = before, word, after
+		return []string{word + "x"}
+	})
+
+	reader.statementHead = "select *"
+	head, completions, tail = reader.completeWord("from us WHERE", 7)
+	assert.Equal(t, "from ", head)
+	assert.Equal(t, []string{"usx"}, completions)
+	assert.Equal(t, " WHERE", tail)
+	assert.Equal(t, "select * from ", gotBefore)
+	assert.Equal(t, "us", gotWord)
+	assert.Equal(t, " WHERE", gotAfter)
+
+	head, _, _ = reader.completeWord("select 1; select a,ид", 21)
+	assert.Equal(t, "select 1; select a,", head)
+	assert.Equal(t, " select a,", gotBefore)
+	assert.Equal(t, "ид", gotWord)
+}
@@ -0,0 +1,86 @@
+package sqlreader
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/peterh/liner"
+)
+
+// terminalSource reads lines from the terminal with line editing and history.
+type terminalSource struct {
+	state       *liner.State
+	historyFile string
+}
+
+// NewTerminalReader returns a new Reader with line editing and history for the terminal.
+// History is loaded from historyFile and saved back on Close, empty historyFile disables it.
+func NewTerminalReader(historyFile string) (*Reader, error) {
+	state := liner.NewLiner()
+	state.SetCtrlCAborts(true)
+	state.SetMultiLineMode(true)
+	state.SetTabCompletionStyle(liner.TabPrints)
+
+	source := &terminalSource{
+		state:       state,
+		historyFile: historyFile,
+	}
+
+	if historyFile != "" {
+		file, err := os.Open(historyFile)
+		if err != nil && !os.IsNotExist(err) {
+			_ = state.Close()
+			return nil, err
+		}
+
+		if err == nil {
+			_, err = state.ReadHistory(file)
+			_ = file.Close()
+			if err != nil {
+				_ = state.Close()
+				return nil, err
+			}
+		}
+	}
+
+	reader := newReader(source)
+	state.SetWordCompleter(reader.completeWord)
+
+	return reader, nil
+}
+
+func (s *terminalSource) readLine(_ io.Writer, prompt string) (string, error) {
+	line, err := s.state.Prompt(prompt)
+	if errors.Is(err, liner.ErrPromptAborted) {
+		return "", ErrInterrupted
+	}
+
+	return line, err
+}
+
+func (s *terminalSource) appendHistory(entry string) {
+	s.state.AppendHistory(entry)
+}
+
+func (s *terminalSource) close() error {
+	defer func() {
+		_ = s.state.Close()
+	}()
+
+	if s.historyFile == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(s.historyFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.state.WriteHistory(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
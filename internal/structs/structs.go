@@ -1,4 +1,4 @@
-// Package structs contains queue, stack and tree structs.
+// Package structs contains queue and stack structs.
 package structs
 
 import "errors"